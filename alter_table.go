@@ -0,0 +1,251 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type alterKind uint8
+
+const (
+	alterAddColumn alterKind = iota + 1
+	alterDropColumn
+	alterRenameColumn
+	alterModifyColumn
+	alterRenameTable
+	alterRaw
+)
+
+type alterAction struct {
+	kind   alterKind
+	column ColumnDef
+	name   string
+	rename string
+}
+
+func (a alterAction) isRename() bool {
+	return a.kind == alterRenameColumn || a.kind == alterRenameTable
+}
+
+type AlterTableBuilder struct {
+	dialect Dialect
+	session Session
+	table   string
+	actions []alterAction
+}
+
+func NewAlterTableBuilder() *AlterTableBuilder {
+	var ab = &AlterTableBuilder{}
+	return ab
+}
+
+func (ab *AlterTableBuilder) Clone() *AlterTableBuilder {
+	if ab == nil {
+		return nil
+	}
+	var nab = &AlterTableBuilder{}
+	nab.dialect = ab.dialect
+	nab.session = ab.session
+	nab.table = ab.table
+	nab.actions = append([]alterAction(nil), ab.actions...)
+	return nab
+}
+
+func (ab *AlterTableBuilder) Reset() {
+	ab.dialect = nil
+	ab.session = nil
+	ab.table = ""
+	ab.actions = ab.actions[:0]
+}
+
+func (ab *AlterTableBuilder) UseDialect(dialect Dialect) *AlterTableBuilder {
+	ab.dialect = dialect
+	return ab
+}
+
+func (ab *AlterTableBuilder) UseSession(session Session) *AlterTableBuilder {
+	ab.session = session
+	if ab.session != nil {
+		ab.dialect = ab.session.Dialect()
+	}
+	return ab
+}
+
+func (ab *AlterTableBuilder) Table(table string) *AlterTableBuilder {
+	ab.table = table
+	return ab
+}
+
+func (ab *AlterTableBuilder) AddColumn(column ColumnDef) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterAddColumn, column: column})
+	return ab
+}
+
+func (ab *AlterTableBuilder) DropColumn(column string) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterDropColumn, name: column})
+	return ab
+}
+
+func (ab *AlterTableBuilder) RenameColumn(column, newName string) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterRenameColumn, name: column, rename: newName})
+	return ab
+}
+
+// ModifyColumn 修改列的定义，MySQL 生成 MODIFY COLUMN，PostgreSQL 分别修改列的类型、是否可以为 NULL 以及默认值，SQLite 不支持。
+func (ab *AlterTableBuilder) ModifyColumn(column ColumnDef) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterModifyColumn, column: column})
+	return ab
+}
+
+func (ab *AlterTableBuilder) RenameTo(table string) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterRenameTable, rename: table})
+	return ab
+}
+
+// Action 添加其它的修改，原样写入 SQL，如 Action("ADD CONSTRAINT uk_email UNIQUE (email)")。
+func (ab *AlterTableBuilder) Action(action string) *AlterTableBuilder {
+	ab.actions = append(ab.actions, alterAction{kind: alterRaw, name: action})
+	return ab
+}
+
+func (ab *AlterTableBuilder) Validate() error {
+	if len(ab.table) == 0 {
+		return errors.New("dbs: alter table clause must specify a table")
+	}
+	if len(ab.actions) == 0 {
+		return errors.New("dbs: alter table clause must specify at least one action")
+	}
+	return nil
+}
+
+func (ab *AlterTableBuilder) Write(w Writer) (err error) {
+	if err = ab.Validate(); err != nil {
+		return err
+	}
+
+	var syntax = ddlSyntaxOf(ab.dialect)
+	if len(ab.actions) > 1 {
+		switch syntax {
+		case ddlSQLite:
+			return errors.New("dbs: sqlite alter table clause supports only one action")
+		case ddlPostgres:
+			for _, action := range ab.actions {
+				if action.isRename() {
+					return errors.New("dbs: postgres alter table clause can not combine rename with other actions")
+				}
+			}
+		}
+	}
+
+	if _, err = w.WriteString("ALTER TABLE "); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(ab.table); err != nil {
+		return err
+	}
+	if err = w.WriteByte(' '); err != nil {
+		return err
+	}
+
+	for idx, action := range ab.actions {
+		if idx > 0 {
+			if _, err = w.WriteString(", "); err != nil {
+				return err
+			}
+		}
+		if err = writeAlterAction(w, syntax, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeAlterAction(w Writer, syntax ddlSyntax, action alterAction) (err error) {
+	switch action.kind {
+	case alterAddColumn:
+		if _, err = w.WriteString("ADD COLUMN "); err != nil {
+			return err
+		}
+		return writeColumnDef(w, syntax, action.column)
+	case alterDropColumn:
+		if _, err = w.WriteString("DROP COLUMN "); err != nil {
+			return err
+		}
+		_, err = w.WriteString(action.name)
+		return err
+	case alterRenameColumn:
+		if _, err = w.WriteString("RENAME COLUMN "); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(action.name); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(" TO "); err != nil {
+			return err
+		}
+		_, err = w.WriteString(action.rename)
+		return err
+	case alterModifyColumn:
+		switch syntax {
+		case ddlSQLite:
+			return errors.New("dbs: sqlite does not support modifying a column")
+		case ddlPostgres:
+			return writePostgresModifyColumn(w, action.column)
+		}
+		if _, err = w.WriteString("MODIFY COLUMN "); err != nil {
+			return err
+		}
+		return writeColumnDef(w, syntax, action.column)
+	case alterRenameTable:
+		if _, err = w.WriteString("RENAME TO "); err != nil {
+			return err
+		}
+		_, err = w.WriteString(action.rename)
+		return err
+	}
+	_, err = w.WriteString(action.name)
+	return err
+}
+
+// writePostgresModifyColumn 生成 ALTER COLUMN c TYPE t, ALTER COLUMN c SET NOT NULL, ALTER COLUMN c SET DEFAULT x。
+func writePostgresModifyColumn(w Writer, column ColumnDef) (err error) {
+	if column.Name == "" {
+		return errors.New("dbs: column definition must specify a name")
+	}
+	var columnType string
+	if columnType, err = ddlPostgres.columnType(column); err != nil {
+		return err
+	}
+
+	var prefix = "ALTER COLUMN " + column.Name
+	var actions = []string{prefix + " TYPE " + columnType}
+	if column.NotNull {
+		actions = append(actions, prefix+" SET NOT NULL")
+	} else {
+		actions = append(actions, prefix+" DROP NOT NULL")
+	}
+	if column.Default != "" {
+		actions = append(actions, prefix+" SET DEFAULT "+column.Default)
+	} else {
+		actions = append(actions, prefix+" DROP DEFAULT")
+	}
+	return writeNames(w, actions)
+}
+
+func (ab *AlterTableBuilder) SQL() (string, []any, error) {
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	buffer.UseDialect(ab.dialect)
+	buffer.UseConverter(converterFromSession(ab.session))
+
+	if err := ab.Write(buffer); err != nil {
+		return "", nil, err
+	}
+	return buffer.String(), buffer.Arguments(), nil
+}
+
+func (ab *AlterTableBuilder) Exec(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, ab.session, ab)
+}
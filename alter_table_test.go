@@ -0,0 +1,44 @@
+package dbs_test
+
+import (
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+func TestAlterTableBuilder(t *testing.T) {
+	var ab = dbs.NewAlterTableBuilder()
+	ab.Table("user")
+	ab.AddColumn(dbs.ColumnDef{Name: "age", Type: "INT", NotNull: true, Default: "0"})
+	ab.ModifyColumn(dbs.ColumnDef{Name: "name", Type: "VARCHAR(64)", NotNull: true})
+	ab.DropColumn("nickname")
+	checkClause(t, ab, "ALTER TABLE user ADD COLUMN age INT NOT NULL DEFAULT 0, MODIFY COLUMN name VARCHAR(64) NOT NULL, DROP COLUMN nickname", nil)
+
+	ab.UseDialect(postgres.Dialect())
+	checkClause(t, ab, "ALTER TABLE user ADD COLUMN age INT NOT NULL DEFAULT 0, ALTER COLUMN name TYPE VARCHAR(64), ALTER COLUMN name SET NOT NULL, ALTER COLUMN name DROP DEFAULT, DROP COLUMN nickname", nil)
+
+	ab.UseDialect(sqlite.Dialect())
+	if _, _, err := ab.SQL(); err == nil {
+		t.Fatal("SQLite 不支持多个修改，应该返回错误")
+	}
+
+	var rb = dbs.NewAlterTableBuilder()
+	rb.UseDialect(sqlite.Dialect())
+	rb.Table("user")
+	rb.RenameColumn("name", "username")
+	checkClause(t, rb, "ALTER TABLE user RENAME COLUMN name TO username", nil)
+
+	var nrb = rb.Clone()
+	nrb.Reset()
+	nrb.Table("user")
+	nrb.Action("ADD CONSTRAINT uk_email UNIQUE (email)")
+	nrb.RenameTo("account")
+	checkClause(t, nrb, "ALTER TABLE user ADD CONSTRAINT uk_email UNIQUE (email), RENAME TO account", nil)
+
+	nrb.UseDialect(postgres.Dialect())
+	if _, _, err := nrb.SQL(); err == nil {
+		t.Fatal("PostgreSQL 不支持重命名与其它修改同时使用，应该返回错误")
+	}
+}
@@ -0,0 +1,486 @@
+package dbs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnsupportedArray = errors.New("dbs: unsupported array type")
+var ErrInvalidArray = errors.New("dbs: invalid array literal")
+var ErrInvalidSet = errors.New("dbs: set value must not contain ','")
+
+// StringArray 对应 PostgreSQL 的 text[]、varchar[] 等数组类型。
+type StringArray []string
+
+func (a StringArray) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a))
+}
+
+func (a *StringArray) Scan(src any) error {
+	return scanArray(src, reflect.ValueOf(a).Elem())
+}
+
+// Int64Array 对应 PostgreSQL 的 int2[]、int4[]、int8[] 等数组类型。
+type Int64Array []int64
+
+func (a Int64Array) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a))
+}
+
+func (a *Int64Array) Scan(src any) error {
+	return scanArray(src, reflect.ValueOf(a).Elem())
+}
+
+// Float64Array 对应 PostgreSQL 的 float4[]、float8[]、numeric[] 等数组类型。
+type Float64Array []float64
+
+func (a Float64Array) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a))
+}
+
+func (a *Float64Array) Scan(src any) error {
+	return scanArray(src, reflect.ValueOf(a).Elem())
+}
+
+// BoolArray 对应 PostgreSQL 的 bool[] 类型。
+type BoolArray []bool
+
+func (a BoolArray) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a))
+}
+
+func (a *BoolArray) Scan(src any) error {
+	return scanArray(src, reflect.ValueOf(a).Elem())
+}
+
+// TimeArray 对应 PostgreSQL 的 timestamp[]、timestamptz[]、date[] 等数组类型。
+type TimeArray []time.Time
+
+func (a TimeArray) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a))
+}
+
+func (a *TimeArray) Scan(src any) error {
+	return scanArray(src, reflect.ValueOf(a).Elem())
+}
+
+// Array 将切片包装为 PostgreSQL 数组，用于绑定参数或者扫描查询结果，支持 []string、[]int64、[]float64、[]bool、[]time.Time 以及它们的指针：
+//
+//	sb.Where("id = ANY(?)", dbs.Array(ids))
+//	rows.Scan(dbs.Array(&tags))
+func Array(a any) interface {
+	driver.Valuer
+	sql.Scanner
+} {
+	switch raw := a.(type) {
+	case []string:
+		return (*StringArray)(&raw)
+	case *[]string:
+		return (*StringArray)(raw)
+	case []int64:
+		return (*Int64Array)(&raw)
+	case *[]int64:
+		return (*Int64Array)(raw)
+	case []float64:
+		return (*Float64Array)(&raw)
+	case *[]float64:
+		return (*Float64Array)(raw)
+	case []bool:
+		return (*BoolArray)(&raw)
+	case *[]bool:
+		return (*BoolArray)(raw)
+	case []time.Time:
+		return (*TimeArray)(&raw)
+	case *[]time.Time:
+		return (*TimeArray)(raw)
+	}
+	return invalidArray{value: a}
+}
+
+type invalidArray struct {
+	value any
+}
+
+func (a invalidArray) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedArray, a.value)
+}
+
+func (a invalidArray) Scan(src any) error {
+	return fmt.Errorf("%w: %T", ErrUnsupportedArray, a.value)
+}
+
+// StringSet 对应 MySQL 的 SET 类型，多个值使用 , 分隔。
+type StringSet []string
+
+func (s StringSet) Value() (driver.Value, error) {
+	return encodeSet(reflect.ValueOf(s))
+}
+
+func (s *StringSet) Scan(src any) error {
+	switch raw := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return decodeSet(raw, reflect.ValueOf(s).Elem())
+	case string:
+		return decodeSet([]byte(raw), reflect.ValueOf(s).Elem())
+	}
+	return fmt.Errorf("dbs: cannot scan %T into %T", src, s)
+}
+
+// arrayCodec 用于设置了 array 标签选项的字段，将切片编码为 PostgreSQL 数组。
+type arrayCodec struct{}
+
+func (arrayCodec) scanType() reflect.Type {
+	return bytesType
+}
+
+func (arrayCodec) encode(column string, value reflect.Value) (any, error) {
+	var nValue, err = encodeArray(value)
+	if err != nil {
+		return nil, fmt.Errorf("dbs: encode array column %q: %w", column, err)
+	}
+	return nValue, nil
+}
+
+func (arrayCodec) decode(column string, src any, dest reflect.Value) error {
+	if err := decodeArray(src.([]byte), dest); err != nil {
+		return fmt.Errorf("dbs: decode array column %q: %w", column, err)
+	}
+	return nil
+}
+
+// setCodec 用于设置了 set 标签选项的字段，将 []string 编码为 MySQL 的 SET。
+type setCodec struct{}
+
+func (setCodec) scanType() reflect.Type {
+	return bytesType
+}
+
+func (setCodec) encode(column string, value reflect.Value) (any, error) {
+	var nValue, err = encodeSet(value)
+	if err != nil {
+		return nil, fmt.Errorf("dbs: encode set column %q: %w", column, err)
+	}
+	return nValue, nil
+}
+
+func (setCodec) decode(column string, src any, dest reflect.Value) error {
+	if err := decodeSet(src.([]byte), dest); err != nil {
+		return fmt.Errorf("dbs: decode set column %q: %w", column, err)
+	}
+	return nil
+}
+
+func isArrayElem(elemType reflect.Type) bool {
+	if elemType == timeType {
+		return true
+	}
+	switch elemType.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// encodeArray 将切片编码为 PostgreSQL 数组的字面量，如 {1,2,3}、{"a","b"}，值为 nil 的切片编码为 NULL。
+func encodeArray(value reflect.Value) (driver.Value, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice || !isArrayElem(value.Type().Elem()) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArray, value.Type())
+	}
+	if value.IsNil() {
+		return nil, nil
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for idx := 0; idx < value.Len(); idx++ {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		writeArrayElem(&builder, value.Index(idx))
+	}
+	builder.WriteByte('}')
+	return builder.String(), nil
+}
+
+func writeArrayElem(builder *strings.Builder, elem reflect.Value) {
+	if elem.Type() == timeType {
+		writeArrayQuote(builder, elem.Interface().(time.Time).Format(time.RFC3339Nano))
+		return
+	}
+
+	switch elem.Kind() {
+	case reflect.String:
+		writeArrayQuote(builder, elem.String())
+	case reflect.Bool:
+		if elem.Bool() {
+			builder.WriteByte('t')
+		} else {
+			builder.WriteByte('f')
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		builder.WriteString(strconv.FormatInt(elem.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		builder.WriteString(strconv.FormatUint(elem.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		var f = elem.Float()
+		switch {
+		case math.IsNaN(f):
+			builder.WriteString("NaN")
+		case math.IsInf(f, 1):
+			builder.WriteString("Infinity")
+		case math.IsInf(f, -1):
+			builder.WriteString("-Infinity")
+		default:
+			builder.WriteString(strconv.FormatFloat(f, 'g', -1, elem.Type().Bits()))
+		}
+	}
+}
+
+func writeArrayQuote(builder *strings.Builder, s string) {
+	builder.WriteByte('"')
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] == '"' || s[idx] == '\\' {
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(s[idx])
+	}
+	builder.WriteByte('"')
+}
+
+func scanArray(src any, dest reflect.Value) error {
+	switch raw := src.(type) {
+	case nil:
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	case []byte:
+		return decodeArray(raw, dest)
+	case string:
+		return decodeArray([]byte(raw), dest)
+	}
+	return fmt.Errorf("dbs: cannot scan %T into %s", src, dest.Type())
+}
+
+// decodeArray 解析 PostgreSQL 一维数组的字面量，元素为 NULL 时使用零值。
+func decodeArray(data []byte, dest reflect.Value) error {
+	if dest.Kind() == reflect.Ptr {
+		var nPointer = reflect.New(dest.Type().Elem())
+		if err := decodeArray(data, nPointer.Elem()); err != nil {
+			return err
+		}
+		dest.Set(nPointer)
+		return nil
+	}
+
+	if dest.Kind() != reflect.Slice || !isArrayElem(dest.Type().Elem()) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedArray, dest.Type())
+	}
+
+	var elems, nulls, err = parseArray(string(data))
+	if err != nil {
+		return err
+	}
+
+	var list = reflect.MakeSlice(dest.Type(), len(elems), len(elems))
+	for idx, elem := range elems {
+		if nulls[idx] {
+			continue
+		}
+		if err = parseArrayElem(elem, list.Index(idx)); err != nil {
+			return err
+		}
+	}
+	dest.Set(list)
+	return nil
+}
+
+// parseArray 将数组的字面量拆分为元素，nulls 标记对应的元素是否为 NULL。
+func parseArray(s string) (elems []string, nulls []bool, err error) {
+	s = strings.TrimSpace(s)
+	// 跳过维度信息，如 [1:3]={1,2,3}
+	if strings.HasPrefix(s, "[") {
+		if pos := strings.IndexByte(s, '='); pos != -1 {
+			s = s[pos+1:]
+		}
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidArray, s)
+	}
+	s = s[1 : len(s)-1]
+	if strings.TrimSpace(s) == "" {
+		return []string{}, []bool{}, nil
+	}
+
+	for pos := 0; pos <= len(s); {
+		var builder strings.Builder
+		var quoted = false
+
+		for pos < len(s) && s[pos] == ' ' {
+			pos++
+		}
+		if pos < len(s) && s[pos] == '{' {
+			return nil, nil, fmt.Errorf("%w: multi-dimensional array is unsupported", ErrUnsupportedArray)
+		}
+		if pos < len(s) && s[pos] == '"' {
+			quoted = true
+			pos++
+			for ; pos < len(s) && s[pos] != '"'; pos++ {
+				if s[pos] == '\\' && pos+1 < len(s) {
+					pos++
+				}
+				builder.WriteByte(s[pos])
+			}
+			if pos >= len(s) {
+				return nil, nil, fmt.Errorf("%w: unterminated quoted element", ErrInvalidArray)
+			}
+			pos++
+			for pos < len(s) && s[pos] == ' ' {
+				pos++
+			}
+		} else {
+			for ; pos < len(s) && s[pos] != ','; pos++ {
+				if s[pos] == '\\' && pos+1 < len(s) {
+					pos++
+				}
+				builder.WriteByte(s[pos])
+			}
+		}
+
+		if pos < len(s) && s[pos] != ',' {
+			return nil, nil, fmt.Errorf("%w: unexpected %q", ErrInvalidArray, s[pos])
+		}
+
+		var elem = builder.String()
+		if !quoted {
+			elem = strings.TrimSpace(elem)
+		}
+		elems = append(elems, elem)
+		nulls = append(nulls, !quoted && strings.EqualFold(elem, "NULL"))
+		pos++
+	}
+	return elems, nulls, nil
+}
+
+var arrayTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseArrayElem(s string, dest reflect.Value) error {
+	if dest.Type() == timeType {
+		for _, layout := range arrayTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				dest.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: cannot parse %q as time", ErrInvalidArray, s)
+	}
+
+	switch dest.Kind() {
+	case reflect.String:
+		dest.SetString(s)
+	case reflect.Bool:
+		var b, err = strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArray, err)
+		}
+		dest.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i, err = strconv.ParseInt(s, 10, dest.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArray, err)
+		}
+		dest.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u, err = strconv.ParseUint(s, 10, dest.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArray, err)
+		}
+		dest.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f, err = strconv.ParseFloat(s, dest.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArray, err)
+		}
+		dest.SetFloat(f)
+	}
+	return nil
+}
+
+// encodeSet 将 []string 编码为 MySQL 的 SET，如 a,b,c，值为 nil 的切片编码为 NULL。
+func encodeSet(value reflect.Value) (driver.Value, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.String {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArray, value.Type())
+	}
+	if value.IsNil() {
+		return nil, nil
+	}
+
+	var builder strings.Builder
+	for idx := 0; idx < value.Len(); idx++ {
+		var elem = value.Index(idx).String()
+		if strings.IndexByte(elem, ',') != -1 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSet, elem)
+		}
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(elem)
+	}
+	return builder.String(), nil
+}
+
+func decodeSet(data []byte, dest reflect.Value) error {
+	if dest.Kind() == reflect.Ptr {
+		var nPointer = reflect.New(dest.Type().Elem())
+		if err := decodeSet(data, nPointer.Elem()); err != nil {
+			return err
+		}
+		dest.Set(nPointer)
+		return nil
+	}
+
+	if dest.Kind() != reflect.Slice || dest.Type().Elem().Kind() != reflect.String {
+		return fmt.Errorf("%w: %s", ErrUnsupportedArray, dest.Type())
+	}
+
+	var elems []string
+	if len(data) > 0 {
+		elems = strings.Split(string(data), ",")
+	}
+	var list = reflect.MakeSlice(dest.Type(), len(elems), len(elems))
+	for idx, elem := range elems {
+		list.Index(idx).SetString(elem)
+	}
+	dest.Set(list)
+	return nil
+}
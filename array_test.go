@@ -0,0 +1,124 @@
+package dbs_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+)
+
+func TestArray_Value(t *testing.T) {
+	var created = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var tests = []struct {
+		Value  driver.Valuer
+		Expect driver.Value
+	}{
+		{Value: dbs.Array([]string{"a", "b c", `d"e`, `f\g`, ""}), Expect: `{"a","b c","d\"e","f\\g",""}`},
+		{Value: dbs.Array([]int64{1, -2, 3}), Expect: "{1,-2,3}"},
+		{Value: dbs.Array([]float64{1.5, math.Inf(1), math.Inf(-1)}), Expect: "{1.5,Infinity,-Infinity}"},
+		{Value: dbs.Array([]bool{true, false}), Expect: "{t,f}"},
+		{Value: dbs.Array([]time.Time{created}), Expect: `{"2024-01-02T03:04:05Z"}`},
+		{Value: dbs.Array([]string{}), Expect: "{}"},
+		{Value: dbs.Array([]string(nil)), Expect: nil},
+		{Value: dbs.StringSet{"a", "b"}, Expect: "a,b"},
+		{Value: dbs.StringSet(nil), Expect: nil},
+	}
+
+	for _, test := range tests {
+		var actual, err = test.Value.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.Expect {
+			t.Fatalf("期望值: %v, 实际值: %v", test.Expect, actual)
+		}
+	}
+
+	if _, err := dbs.Array([]int{1}).Value(); !errors.Is(err, dbs.ErrUnsupportedArray) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrUnsupportedArray, err)
+	}
+	if _, err := (dbs.StringSet{"a,b"}).Value(); !errors.Is(err, dbs.ErrInvalidSet) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrInvalidSet, err)
+	}
+}
+
+func TestArray_Scan(t *testing.T) {
+	var tags []string
+	if err := dbs.Array(&tags).Scan([]byte(`{a,"b c","d\"e",NULL,"NULL"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"a", "b c", `d"e`, "", "NULL"}; !reflect.DeepEqual(tags, expect) {
+		t.Fatalf("期望值: %q, 实际值: %q", expect, tags)
+	}
+
+	var ids []int64
+	if err := dbs.Array(&ids).Scan("[0:2]={1,2,3}"); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []int64{1, 2, 3}; !reflect.DeepEqual(ids, expect) {
+		t.Fatalf("期望值: %v, 实际值: %v", expect, ids)
+	}
+
+	var flags []bool
+	if err := dbs.Array(&flags).Scan("{t,f,true}"); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []bool{true, false, true}; !reflect.DeepEqual(flags, expect) {
+		t.Fatalf("期望值: %v, 实际值: %v", expect, flags)
+	}
+
+	var times []time.Time
+	if err := dbs.Array(&times).Scan(`{"2024-01-02 03:04:05+08","2024-01-02"}`); err != nil {
+		t.Fatal(err)
+	}
+	if len(times) != 2 || times[0].Unix() != time.Date(2024, 1, 1, 19, 4, 5, 0, time.UTC).Unix() {
+		t.Fatalf("解析时间错误: %v", times)
+	}
+
+	if err := dbs.Array(&ids).Scan(nil); err != nil || ids != nil {
+		t.Fatalf("期望 NULL 解析为 nil, 实际值: %v, 错误: %v", ids, err)
+	}
+	if err := dbs.Array(&ids).Scan("{{1,2},{3,4}}"); !errors.Is(err, dbs.ErrUnsupportedArray) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrUnsupportedArray, err)
+	}
+	if err := dbs.Array(&ids).Scan("1,2"); !errors.Is(err, dbs.ErrInvalidArray) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrInvalidArray, err)
+	}
+
+	var set dbs.StringSet
+	if err := set.Scan([]byte("a,b")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := (dbs.StringSet{"a", "b"}); !reflect.DeepEqual(set, expect) {
+		t.Fatalf("期望值: %v, 实际值: %v", expect, set)
+	}
+}
+
+type arrayUser struct {
+	Id    int64    `sql:"id"`
+	Tags  []string `sql:"tags;array"`
+	Roles []string `sql:"roles;set"`
+	Ids   []int64  `sql:"ids;array"`
+}
+
+func TestMapper_EncodeArray(t *testing.T) {
+	var mapper = dbs.NewMapper("sql")
+
+	var fieldValues, err = mapper.Encode(arrayUser{Id: 1, Tags: []string{"a", "b"}, Roles: []string{"admin", "user"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values = make([]any, 0, len(fieldValues))
+	for _, fieldValue := range fieldValues {
+		values = append(values, fieldValue.Value)
+	}
+	if expect := []any{int64(1), `{"a","b"}`, "admin,user", nil}; !reflect.DeepEqual(values, expect) {
+		t.Fatalf("期望值: %v, 实际值: %v", expect, values)
+	}
+}
@@ -42,6 +42,13 @@ type Buffer struct {
 	arguments        []any
 	dialect          Dialect
 	placeholderCount int
+	strategy         SliceStrategy
+	converter        ConverterMapper
+	chunkSize        int // 大于 0 时拆分超过该长度的切片参数，参考 SliceChunk
+	chunkOffset      int
+	chunkTotal       int
+	chunkLength      int // 本次写入的切片元素数量
+	chunkPos         int // 拆分的切片在 SQL 语句中的位置
 }
 
 func NewBuffer() *Buffer {
@@ -50,6 +57,13 @@ func NewBuffer() *Buffer {
 	buffer.arguments = buffer.arguments[:0]
 	buffer.dialect = nil
 	buffer.placeholderCount = 0
+	buffer.strategy = SliceDefault
+	buffer.converter = nil
+	buffer.chunkSize = 0
+	buffer.chunkOffset = 0
+	buffer.chunkTotal = 0
+	buffer.chunkLength = 0
+	buffer.chunkPos = 0
 	return buffer
 }
 
@@ -61,6 +75,34 @@ func (b *Buffer) UseDialect(dialect Dialect) {
 	b.dialect = dialect
 }
 
+func (b *Buffer) UseSliceStrategy(strategy SliceStrategy) {
+	b.strategy = strategy
+}
+
+func (b *Buffer) SliceStrategy() SliceStrategy {
+	return b.strategy
+}
+
+// UseConverter 设置绑定参数时使用的转换函数，参考 ConverterMapper。
+func (b *Buffer) UseConverter(converter ConverterMapper) {
+	b.converter = converter
+}
+
+// chunk 返回长度为 n 的切片参数在本次生成 SQL 语句时需要写入的范围。
+func (b *Buffer) chunk(n int) (int, int) {
+	if b.chunkSize <= 0 || b.chunkTotal > 0 || n <= b.chunkSize {
+		return 0, n
+	}
+	b.chunkTotal = n
+	b.chunkPos = b.Len()
+	var start, end = b.chunkOffset, b.chunkOffset + b.chunkSize
+	if end > n {
+		end = n
+	}
+	b.chunkLength = end - start
+	return start, end
+}
+
 func (b *Buffer) WriteArgument(flag uint8, arg any) (err error) {
 	if flag&FlagArgument == FlagArgument {
 		b.arguments = append(b.arguments, arg)
@@ -4,13 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"reflect"
 	"time"
 )
 
 type Builder struct {
-	dialect Dialect
-	session Session
-	clauses *Clauses
+	dialect  Dialect
+	session  Session
+	strategy SliceStrategy
+	clauses  *Clauses
 }
 
 func NewBuilder() *Builder {
@@ -18,9 +20,22 @@ func NewBuilder() *Builder {
 	return sb
 }
 
+func (rb *Builder) Clone() *Builder {
+	if rb == nil {
+		return nil
+	}
+	var nrb = &Builder{}
+	nrb.dialect = rb.dialect
+	nrb.session = rb.session
+	nrb.strategy = rb.strategy
+	nrb.clauses = rb.clauses.Clone()
+	return nrb
+}
+
 func (rb *Builder) Reset() {
 	rb.dialect = nil
 	rb.session = nil
+	rb.strategy = SliceDefault
 	rb.clauses.reset()
 }
 
@@ -37,6 +52,11 @@ func (rb *Builder) UseSession(session Session) *Builder {
 	return rb
 }
 
+func (rb *Builder) UseSliceStrategy(strategy SliceStrategy) *Builder {
+	rb.strategy = strategy
+	return rb
+}
+
 func (rb *Builder) Append(sql any, args ...any) *Builder {
 	if rb.clauses == nil {
 		rb.clauses = NewClauses(' ')
@@ -67,6 +87,8 @@ func (rb *Builder) SQL() (string, []any, error) {
 	defer buffer.Release()
 
 	buffer.UseDialect(rb.dialect)
+	buffer.UseSliceStrategy(sliceStrategy(rb.strategy, rb.session))
+	buffer.UseConverter(converterFromSession(rb.session))
 
 	if err := rb.Write(buffer); err != nil {
 		return "", nil, err
@@ -74,6 +96,10 @@ func (rb *Builder) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+func (rb *Builder) chunk(offset int) (string, []any, int, error) {
+	return chunkSQL(rb, rb.session, rb.dialect, sliceStrategy(rb.strategy, rb.session), offset)
+}
+
 func (rb *Builder) Scan(ctx context.Context, dest any) error {
 	return scan(ctx, rb.session, rb, dest)
 }
@@ -104,6 +130,20 @@ func depthFromContext(ctx context.Context) int {
 }
 
 func scan(ctx context.Context, session Session, clause SQLClause, dest any) (err error) {
+	var offset int
+	for {
+		if offset, err = scanChunk(withDepth(ctx, depthFromContext(ctx)+1), session, clause, offset, dest); err != nil {
+			return err
+		}
+		if offset == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// scanChunk 执行从 offset 开始拆分的语句，返回下一条语句的起始位置，没有需要执行的语句时返回 0。
+func scanChunk(ctx context.Context, session Session, clause SQLClause, offset int, dest any) (next int, err error) {
 	var query string
 	var args []any
 	var rowsAffected int
@@ -115,21 +155,36 @@ func scan(ctx context.Context, session Session, clause SQLClause, dest any) (err
 		}()
 	}
 
-	if query, args, err = clause.SQL(); err != nil {
-		return err
+	if raw, ok := clause.(chunkable); ok {
+		if query, args, next, err = raw.chunk(offset); err != nil {
+			return 0, err
+		}
+		if (offset > 0 || next > 0) && !isSliceDestination(dest) {
+			return 0, ErrChunkDestination
+		}
+	} else if query, args, err = clause.SQL(); err != nil {
+		return 0, err
 	}
 
 	var rows *sql.Rows
 	rows, err = session.QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer rows.Close()
 
-	if rowsAffected, err = session.Mapper().Decode(rows, dest); err != nil && !errors.Is(err, ErrNoRows) {
-		return err
+	if rowsAffected, err = decode(ctx, session.Mapper(), rows, dest); err != nil && !errors.Is(err, ErrNoRows) {
+		return 0, err
 	}
-	return nil
+	return next, nil
+}
+
+func isSliceDestination(dest any) bool {
+	var destType = reflect.TypeOf(dest)
+	for destType != nil && destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	return destType != nil && (destType.Kind() == reflect.Slice || isKeyedMap(destType))
 }
 
 func scanRow(ctx context.Context, session Session, clause SQLClause, dest ...any) (err error) {
@@ -12,3 +12,18 @@ func TestBuilder(t *testing.T) {
 	rb.Raw("WHERE id = ?", 10)
 	t.Log(rb.SQL())
 }
+
+func TestBuilder_Clone(t *testing.T) {
+	var rb = dbs.NewBuilder()
+	rb.Raw("SELECT * FROM user")
+
+	var nrb = rb.Clone()
+	nrb.Raw("WHERE id = ?", 10)
+
+	checkClause(t, rb, "SELECT * FROM user", ExpectArgs())
+	checkClause(t, nrb, "SELECT * FROM user WHERE id = ?", ExpectArgs(10))
+
+	var eb = dbs.NewBuilder()
+	eb.Reset()
+	eb.Clone().Reset()
+}
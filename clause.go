@@ -3,6 +3,7 @@ package dbs
 import (
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 )
@@ -15,6 +16,33 @@ type SQLClause interface {
 
 var ErrMissingArgument = errors.New("missing argument")
 var ErrTooManyArguments = errors.New("too many arguments")
+var ErrEmptyArgument = errors.New("dbs: empty slice or array argument")
+
+// ValidationError 描述构建 SQL 语句时校验失败的位置，可以通过 errors.As 获取。
+//
+// Row 为出错的数据行（从 0 开始），与数据行无关时为 -1；Column 为出错的列，无法确定时为空。
+type ValidationError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Row >= 0 && e.Column != "":
+		return fmt.Sprintf("%v (row: %d, column: %s)", e.Err, e.Row, e.Column)
+	case e.Row >= 0:
+		return fmt.Sprintf("%v (row: %d)", e.Err, e.Row)
+	case e.Column != "":
+		return fmt.Sprintf("%v (column: %s)", e.Err, e.Column)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
 
 type Clause struct {
 	sql  any
@@ -36,17 +64,7 @@ func (c Clause) Clone() Clause {
 	} else {
 		nc.sql = c.sql
 	}
-	if len(c.args) > 0 {
-		nc.args = make([]any, len(c.args))
-		for i, arg := range c.args {
-			switch raw := arg.(type) {
-			case SQLClause:
-				nc.args[i] = clone(raw)
-			default:
-				nc.args[i] = arg
-			}
-		}
-	}
+	nc.args = cloneValues(c.args)
 	return nc
 }
 
@@ -94,20 +112,39 @@ func buildArgument(w Writer, arg any) (err error) {
 		if err = w.WriteArgument(FlagPlaceholder|FlagArgument, value); err != nil {
 			return err
 		}
+	case []byte:
+		if err = w.WriteArgument(FlagPlaceholder|FlagArgument, raw); err != nil {
+			return err
+		}
 	default:
+		var nArg, converted, err = convertArgument(w, raw)
+		if err != nil {
+			return err
+		}
+		if converted {
+			return w.WriteArgument(FlagPlaceholder|FlagArgument, nArg)
+		}
+
 		var value = reflect.ValueOf(raw)
 		var kind = value.Kind()
 		if kind == reflect.Slice || kind == reflect.Array {
 			if value.Len() == 0 {
-				return errors.New("empty slice or array argument")
+				return ErrEmptyArgument
+			}
+			var start, end = 0, value.Len()
+			if raw, ok := w.(*Buffer); ok {
+				start, end = raw.chunk(end)
 			}
-			for idx := 0; idx < value.Len(); idx++ {
-				if idx != 0 {
+			for idx := start; idx < end; idx++ {
+				if idx != start {
 					if err = w.WriteByte(','); err != nil {
 						return err
 					}
 				}
-				if err = w.WriteArgument(FlagPlaceholder|FlagArgument, value.Index(idx).Interface()); err != nil {
+				if nArg, _, err = convertArgument(w, value.Index(idx).Interface()); err != nil {
+					return err
+				}
+				if err = w.WriteArgument(FlagPlaceholder|FlagArgument, nArg); err != nil {
 					return err
 				}
 			}
@@ -128,12 +165,36 @@ func buildClause(w Writer, sql string, args []any) ([]any, error) {
 		if pos == -1 {
 			break
 		}
-		if _, err = w.WriteString(sql[:pos]); err != nil {
+		var head = sql[:pos]
+		if len(args) > 0 && isArrayArgument(args[0]) && sliceStrategyFromWriter(w) == SliceArray && strings.HasPrefix(strings.TrimLeft(sql[pos+1:], " "), ")") {
+			if nHead, op, ok := splitIn(head); ok {
+				if reflect.ValueOf(args[0]).Len() == 0 {
+					return nil, &ValidationError{Row: -1, Column: columnBefore(head), Err: ErrEmptyArgument}
+				}
+				if _, err = w.WriteString(nHead); err != nil {
+					return nil, err
+				}
+				if _, err = w.WriteString(op); err != nil {
+					return nil, err
+				}
+				if err = w.WriteArgument(FlagPlaceholder|FlagArgument, arrayArgument{value: args[0]}); err != nil {
+					return nil, err
+				}
+				args = args[1:]
+				sql = sql[pos+1:]
+				continue
+			}
+		}
+
+		if _, err = w.WriteString(head); err != nil {
 			return nil, err
 		}
 
 		if len(args) > 0 {
 			if err = buildArgument(w, args[0]); err != nil {
+				if errors.Is(err, ErrEmptyArgument) {
+					return nil, &ValidationError{Row: -1, Column: columnBefore(sql[:pos]), Err: err}
+				}
 				return nil, err
 			}
 			args = args[1:]
@@ -152,6 +213,23 @@ func buildClause(w Writer, sql string, args []any) ([]any, error) {
 	return args, nil
 }
 
+// columnBefore 从占位符之前的 SQL 片段中提取列名，如 "id IN (" 返回 "id"。
+func columnBefore(sql string) string {
+	sql = strings.TrimRight(sql, " (")
+	if n := len(sql); n >= 2 && strings.EqualFold(sql[n-2:], "IN") {
+		sql = strings.TrimRight(sql[:n-2], " ")
+		if n = len(sql); n >= 3 && strings.EqualFold(sql[n-3:], "NOT") {
+			sql = strings.TrimRight(sql[:n-3], " ")
+		}
+	} else {
+		sql = strings.TrimRight(sql, " =<>!")
+	}
+	if pos := strings.LastIndexAny(sql, " (,"); pos != -1 {
+		sql = sql[pos+1:]
+	}
+	return sql
+}
+
 type Clauses struct {
 	sep     byte
 	clauses []SQLClause
@@ -345,7 +423,11 @@ func (sc Set) Write(w Writer) (err error) {
 			return err
 		}
 	default:
-		if err = w.WriteArgument(FlagPlaceholder|FlagArgument, raw); err != nil {
+		var nValue, _, err = convertArgument(w, raw)
+		if err != nil {
+			return err
+		}
+		if err = w.WriteArgument(FlagPlaceholder|FlagArgument, nValue); err != nil {
 			return err
 		}
 	}
@@ -365,6 +447,9 @@ func (sc Set) SQL() (string, []any, error) {
 type Parts []string
 
 func (ps Parts) Clone() Parts {
+	if ps == nil {
+		return nil
+	}
 	var ncps = make([]string, len(ps))
 	copy(ncps, ps)
 	return ncps
@@ -400,6 +485,19 @@ func (ps Parts) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+// errorClause 用于在链式调用中记录错误，生成 SQL 语句时返回该错误。
+type errorClause struct {
+	err error
+}
+
+func (ec errorClause) Write(w Writer) error {
+	return ec.err
+}
+
+func (ec errorClause) SQL() (string, []any, error) {
+	return "", nil, ec.err
+}
+
 func clone(clause SQLClause) SQLClause {
 	if clause == nil {
 		return nil
@@ -415,7 +513,33 @@ func clone(clause SQLClause) SQLClause {
 		return raw.Clone()
 	case Parts:
 		return raw.Clone()
+	case *Builder:
+		return raw.Clone()
+	case *SelectBuilder:
+		return raw.Clone()
+	case *InsertBuilder:
+		return raw.Clone()
+	case *UpdateBuilder:
+		return raw.Clone()
+	case *DeleteBuilder:
+		return raw.Clone()
 	default:
 		return clause
 	}
 }
+
+func cloneValues(values []any) []any {
+	if len(values) == 0 {
+		return nil
+	}
+	var nValues = make([]any, len(values))
+	for i, value := range values {
+		switch raw := value.(type) {
+		case SQLClause:
+			nValues[i] = clone(raw)
+		default:
+			nValues[i] = value
+		}
+	}
+	return nValues
+}
@@ -267,6 +267,18 @@ func TestClause_SQL(t *testing.T) {
 			Clause:    dbs.SQL("id = ?", 1, "foo"),
 			ExpectErr: dbs.ErrTooManyArguments,
 		},
+		{
+			Clause:    dbs.SQL("id IN (?)", []int{}),
+			ExpectErr: dbs.ErrEmptyArgument,
+		},
+		{
+			Clause:    dbs.NewInsertBuilder().Table("user").Columns("name,age").Values(1, 2).Values(3),
+			ExpectErr: dbs.ErrInsertValuesCountMismatch,
+		},
+		{
+			Clause:    dbs.NewUpdateBuilder().Table("user").Set("name", "n1").Set("name", "n2").Where("id = ?", 1),
+			ExpectErr: dbs.ErrDuplicateSetColumn,
+		},
 	}
 
 	for _, test := range tests {
@@ -281,6 +293,46 @@ func TestClause_SQL(t *testing.T) {
 	}
 }
 
+func TestValidationError(t *testing.T) {
+	var tests = []struct {
+		Clause       dbs.SQLClause
+		ExpectRow    int
+		ExpectColumn string
+	}{
+		{
+			Clause:       dbs.NewSelectBuilder().Table("user").Selects("id").Where("status = ?", 1).Where("id IN (?)", []int{}),
+			ExpectRow:    -1,
+			ExpectColumn: "id",
+		},
+		{
+			Clause:       dbs.NewSelectBuilder().Table("user u").Selects("u.id").Where("u.id NOT IN (?)", []string{}),
+			ExpectRow:    -1,
+			ExpectColumn: "u.id",
+		},
+		{
+			Clause:    dbs.NewInsertBuilder().Table("user").Columns("name", "age").Values(1, 2).Values(3, 4).Values(5, 6, 7),
+			ExpectRow: 2,
+		},
+		{
+			Clause:       dbs.NewUpdateBuilder().Table("user").Set("name", "n1").Set("age", 1).Set("age", 2).Where("id = ?", 1),
+			ExpectRow:    -1,
+			ExpectColumn: "age",
+		},
+	}
+
+	for _, test := range tests {
+		_, _, err := test.Clause.SQL()
+
+		var vErr *dbs.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("期望错误: %T, 实际错误: %v", vErr, err)
+		}
+		if vErr.Row != test.ExpectRow || vErr.Column != test.ExpectColumn {
+			t.Fatalf("期望位置: %d %q, 实际位置: %d %q", test.ExpectRow, test.ExpectColumn, vErr.Row, vErr.Column)
+		}
+	}
+}
+
 func BenchmarkClause_SQL(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		var c1 = dbs.SQL("SELECT * FROM user u WHERE u.id = ? AND u.gender = ? ", "1", 2)
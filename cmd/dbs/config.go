@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/mysql"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+// dialects 为 -dialect 支持的数据库以及默认的驱动名称。
+var dialects = map[string]struct {
+	dialect dbs.Dialect
+	driver  string
+}{
+	"mysql":    {mysql.Dialect(), "mysql"},
+	"postgres": {postgres.Dialect(), "postgres"},
+	"sqlite":   {sqlite.Dialect(), "sqlite"},
+}
+
+// config 为所有需要连接数据库的命令共用的参数。
+type config struct {
+	dsn     string
+	dialect string
+	driver  string
+	dryRun  bool
+	verbose bool
+}
+
+func (c *config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.dsn, "dsn", os.Getenv("DBS_DSN"), "data source name, defaults to $DBS_DSN")
+	fs.StringVar(&c.dialect, "dialect", os.Getenv("DBS_DIALECT"), "mysql, postgres or sqlite, defaults to $DBS_DIALECT")
+	fs.StringVar(&c.driver, "driver", "", "database/sql driver name, defaults to the dialect name")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "print the statements without executing them")
+	fs.BoolVar(&c.verbose, "v", false, "log executed statements")
+}
+
+// open 根据 -dsn、-dialect 以及 -driver 连接数据库。
+func (c *config) open() (*dbs.DB, error) {
+	if c.dsn == "" {
+		return nil, errors.New("missing -dsn or $DBS_DSN")
+	}
+	var found, ok = dialects[c.dialect]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect %q, expected mysql, postgres or sqlite", c.dialect)
+	}
+	var driver = c.driver
+	if driver == "" {
+		driver = found.driver
+	}
+
+	// 迁移时加锁以及执行迁移分别使用一个连接
+	var db, err = dbs.Open(driver, c.dsn, 4, 2)
+	if err != nil {
+		return nil, err
+	}
+	db.UseDialect(found.dialect)
+	if !c.verbose {
+		db.UseLogger(nil)
+	}
+	return db, nil
+}
+
+// newFlagSet 创建子命令的 FlagSet，解析失败时由调用方返回错误。
+func newFlagSet(name string) *flag.FlagSet {
+	var fs = flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/smartwalle/dbs"
+)
+
+// runExplain 不需要连接数据库，参数依次代入 SQL 语句中的 ? 占位符，$N 占位符代入第 N 个参数，其它形式的占位符（如 :name、@p1）不会被替换。
+func runExplain(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: dbs explain "<sql>" [args...]`)
+	}
+
+	var params = make([]any, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		params = append(params, parseArgument(arg))
+	}
+
+	var query, err = dbs.ExplainSQL(args[0], params)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, query)
+	return err
+}
+
+// parseArgument 将命令行参数转换为对应的类型：整数、浮点数、true/false 以及 NULL，其它参数作为字符串。
+func parseArgument(arg string) any {
+	if strings.EqualFold(arg, "NULL") {
+		return nil
+	}
+	if value, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return value
+	}
+	if value, err := strconv.ParseFloat(arg, 64); err == nil {
+		return value
+	}
+	if arg == "true" || arg == "false" {
+		return arg == "true"
+	}
+	return arg
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runExplain(&buf, []string{"SELECT * FROM user WHERE id = ? AND name = ? AND deleted_at IS ? AND score > ? AND enabled = ?", "10", "bob", "null", "1.5", "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = "SELECT * FROM user WHERE id = 10 AND name = 'bob' AND deleted_at IS NULL AND score > 1.5 AND enabled = true\n"
+	if buf.String() != expect {
+		t.Fatalf("期望输出: %q, 实际输出: %q", expect, buf.String())
+	}
+
+	buf.Reset()
+	if err := runExplain(&buf, []string{"SELECT * FROM user WHERE id = $1 OR parent_id = $1 AND name = $2", "10", "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if expect = "SELECT * FROM user WHERE id = 10 OR parent_id = 10 AND name = 'bob'\n"; buf.String() != expect {
+		t.Fatalf("期望输出: %q, 实际输出: %q", expect, buf.String())
+	}
+
+	if err := runExplain(&buf, nil); err == nil {
+		t.Fatal("没有 SQL 语句时应该返回错误")
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/smartwalle/dbs/cmd/dbs
+
+go 1.18
+
+require (
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/lib/pq v1.10.9
+	github.com/smartwalle/dbs v1.2.5
+)
+
+require filippo.io/edwards25519 v1.1.0 // indirect
+
+replace github.com/smartwalle/dbs => ../../
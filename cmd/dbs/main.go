@@ -0,0 +1,76 @@
+// dbs 是基于 dbs 的命令行工具，用于执行数据库迁移、导出表结构、根据表结构生成结构体以及展开 SQL 语句中的参数。
+//
+// 用法：
+//
+//	dbs migrate up     [flags]          执行所有没有执行过的迁移，-to 指定目标版本
+//	dbs migrate down   [flags]          回滚最后一个迁移，-to 指定目标版本时回滚所有大于该版本的迁移
+//	dbs migrate status [flags]          输出迁移的执行状态
+//	dbs schema dump    [flags] [表名...] 输出表结构的 CREATE TABLE 语句
+//	dbs gen structs    [flags] [表名...] 根据表结构生成带有 sql 标签的结构体
+//	dbs explain "<sql>" [参数...]        将参数代入 SQL 语句中的 ? 或者 $N 占位符
+//
+// 公共参数：
+//
+//	-dsn      数据源，默认为环境变量 DBS_DSN
+//	-dialect  数据库类型，支持 mysql、postgres、sqlite，默认为环境变量 DBS_DIALECT
+//	-driver   database/sql 的驱动名称，默认为 mysql、postgres 以及 sqlite
+//	-dry-run  只输出需要执行的语句，不修改数据库
+//	-v        输出执行的 SQL
+//
+// 内置了 MySQL（github.com/go-sql-driver/mysql）以及 PostgreSQL（github.com/lib/pq）的驱动，
+// 使用 SQLite 时需要在自行构建时导入驱动，并通过 -driver 指定驱动名称。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const kUsage = `usage:
+  dbs migrate up|down|status [flags]
+  dbs schema dump [flags] [tables...]
+  dbs gen structs [flags] [tables...]
+  dbs explain "<sql>" [args...]
+
+run "dbs <command> <subcommand> -h" for the flags of a command.
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "dbs:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, kUsage)
+		return flag.ErrHelp
+	}
+
+	var command, subcommand = args[0], ""
+	if len(args) > 1 {
+		subcommand = args[1]
+	}
+
+	switch {
+	case command == "migrate" && (subcommand == "up" || subcommand == "down" || subcommand == "status"):
+		return runMigrate(ctx, subcommand, args[2:])
+	case command == "schema" && subcommand == "dump":
+		return runSchemaDump(ctx, args[2:])
+	case command == "gen" && subcommand == "structs":
+		return runGenStructs(ctx, args[2:])
+	case command == "explain":
+		return runExplain(os.Stdout, args[1:])
+	}
+
+	fmt.Fprint(os.Stderr, kUsage)
+	return fmt.Errorf("unknown command %q", strings.TrimSpace(command+" "+subcommand))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/smartwalle/dbs/migrate"
+)
+
+func runMigrate(ctx context.Context, subcommand string, args []string) error {
+	var cfg config
+	var fs = newFlagSet("migrate " + subcommand)
+	cfg.register(fs)
+	var dir = fs.String("dir", "migrations", "directory of the <version>_<name>.up.sql and .down.sql files")
+	var table = fs.String("table", "", "history table, defaults to schema_migrations")
+	var to = fs.Int64("to", -1, "target version, up applies versions <= to, down rolls back versions > to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var db, err = cfg.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var opts = []migrate.Option{migrate.WithTable(*table)}
+	if cfg.dryRun {
+		opts = append(opts, migrate.WithDryRun(os.Stdout))
+	}
+	var m = migrate.New(db, opts...)
+	if err = m.AddFS(os.DirFS(*dir), "."); err != nil {
+		return err
+	}
+
+	var migrations []*migrate.Migration
+	switch subcommand {
+	case "up":
+		var version int64 = math.MaxInt64
+		if *to >= 0 {
+			version = *to
+		}
+		migrations, err = m.UpTo(ctx, version)
+	case "down":
+		if *to >= 0 {
+			migrations, err = m.DownTo(ctx, *to)
+		} else {
+			migrations, err = m.Down(ctx)
+		}
+	case "status":
+		return printStatus(ctx, m)
+	}
+
+	// dry run 时已经输出了 SQL，这里只在实际执行时输出执行的迁移
+	if !cfg.dryRun {
+		for _, migration := range migrations {
+			fmt.Printf("%s %d %s\n", subcommand, migration.Version, migration.Name)
+		}
+	}
+	return err
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	var statuses, err = m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		var state, appliedAt = "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		switch {
+		case status.Missing:
+			state += " (missing)"
+		case status.Modified:
+			state += " (modified)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
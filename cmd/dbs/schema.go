@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/smartwalle/dbs/schema"
+)
+
+func runSchemaDump(ctx context.Context, args []string) error {
+	var cfg config
+	var fs = newFlagSet("schema dump")
+	cfg.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var db, err = cfg.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables, err := schema.Inspect(ctx, db, fs.Args()...)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		query, _, err := schema.CreateTable(table).UseDialect(db.Dialect()).SQL()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s;\n\n", query)
+	}
+	return nil
+}
+
+func runGenStructs(ctx context.Context, args []string) error {
+	var cfg config
+	var fs = newFlagSet("gen structs")
+	cfg.register(fs)
+	var pkg = fs.String("package", "model", "package name of the generated file")
+	var output = fs.String("output", "", "output file, defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var db, err = cfg.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables, err := schema.Inspect(ctx, db, fs.Args()...)
+	if err != nil {
+		return err
+	}
+	src, err := schema.NewGenerator(*pkg).Generate(tables...)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" || cfg.dryRun {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0644)
+}
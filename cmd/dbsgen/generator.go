@@ -0,0 +1,823 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	kDBSImport       = "github.com/smartwalle/dbs"
+	kGeneratedHeader = "// Code generated by dbsgen. DO NOT EDIT."
+	kMaxEmbedDepth   = 10
+
+	kTagValueDisable   = "-"
+	kTagValueDefault   = "default"
+	kTagValuePrefix    = "prefix"
+	kTagValuePK        = "pk"
+	kTagValueReadOnly  = "readonly"
+	kTagValueInsert    = "insert"
+	kTagValueUpdate    = "update"
+	kTagValueOmitEmpty = "omitempty"
+	kTagValueJSON      = "json"
+	kTagValueArray     = "array"
+	kTagValueSet       = "set"
+	kTagValueSeparator = ";"
+)
+
+var ErrNoPackage = errors.New("no Go files found")
+
+type options struct {
+	Types  []string
+	Tag    string
+	Output string
+}
+
+// packageInfo 为从源码中收集的包信息。
+type packageInfo struct {
+	fset   *token.FileSet
+	name   string
+	output string
+
+	types   map[string]*ast.TypeSpec
+	files   map[string]*ast.File // 类型所在的文件，用于查找导入的包
+	methods map[string]map[string]*ast.FuncDecl
+	order   []string // 结构体在源码中的顺序
+}
+
+// loadPackage 解析 dir 中除测试文件以及输出文件之外的 Go 文件。
+func loadPackage(dir string, opts options) (*packageInfo, error) {
+	var entries, err = os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fset = token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		var name = entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if opts.Output != "" && name == opts.Output {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoPackage, dir)
+	}
+	return newPackageInfo(fset, files, opts)
+}
+
+// isGenerated 判断文件是否为 dbsgen 生成的文件。
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if comment.Text == kGeneratedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newPackageInfo 收集 files 中的类型以及方法，files 需要按照文件名排序以保证生成的代码稳定。
+func newPackageInfo(fset *token.FileSet, files []*ast.File, opts options) (*packageInfo, error) {
+	var pkg = &packageInfo{}
+	pkg.fset = fset
+	pkg.name = files[0].Name.Name
+	pkg.types = make(map[string]*ast.TypeSpec)
+	pkg.files = make(map[string]*ast.File)
+	pkg.methods = make(map[string]map[string]*ast.FuncDecl)
+
+	pkg.output = opts.Output
+	if pkg.output == "" {
+		pkg.output = pkg.name + "_dbs.go"
+	}
+
+	for _, file := range files {
+		if file.Name.Name != pkg.name {
+			return nil, fmt.Errorf("found packages %s and %s", pkg.name, file.Name.Name)
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					var typeSpec = spec.(*ast.TypeSpec)
+					pkg.types[typeSpec.Name.Name] = typeSpec
+					pkg.files[typeSpec.Name.Name] = file
+					if _, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.TypeParams == nil {
+						pkg.order = append(pkg.order, typeSpec.Name.Name)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					continue
+				}
+				var recv = decl.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					if pkg.methods[ident.Name] == nil {
+						pkg.methods[ident.Name] = make(map[string]*ast.FuncDecl)
+					}
+					pkg.methods[ident.Name][decl.Name.Name] = decl
+				}
+			}
+		}
+	}
+	return pkg, nil
+}
+
+// structType 返回包中名为 name 的结构体。
+func (pkg *packageInfo) structType(name string) *ast.StructType {
+	if spec, ok := pkg.types[name]; ok {
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			return st
+		}
+	}
+	return nil
+}
+
+// stringMethod 判断类型是否以值接收者实现了 func() string 方法，如果方法体直接返回字符串字面量，同时返回该字面量。
+func (pkg *packageInfo) stringMethod(typeName, name string) (bool, string) {
+	var decl = pkg.methods[typeName][name]
+	if decl == nil {
+		return false, ""
+	}
+	if _, ok := decl.Recv.List[0].Type.(*ast.StarExpr); ok {
+		return false, ""
+	}
+	var fnType = decl.Type
+	if fnType.Params.NumFields() != 0 || fnType.Results.NumFields() != 1 {
+		return false, ""
+	}
+	if ident, ok := fnType.Results.List[0].Type.(*ast.Ident); !ok || ident.Name != "string" {
+		return false, ""
+	}
+
+	if decl.Body != nil && len(decl.Body.List) == 1 {
+		if ret, ok := decl.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			if lit, ok := ret.Results[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if value, err := strconv.Unquote(lit.Value); err == nil {
+					return true, value
+				}
+			}
+		}
+	}
+	return true, ""
+}
+
+// isEntity 判断类型是否以值接收者实现了 dbs.Entity 接口，只有这样的类型才能用于 dbs.Repository[E]。
+func (pkg *packageInfo) isEntity(typeName string) bool {
+	var hasTable, _ = pkg.stringMethod(typeName, "TableName")
+	var hasKey, _ = pkg.stringMethod(typeName, "PrimaryKey")
+	return hasTable && hasKey
+}
+
+// field 为结构体中映射到列的字段。
+type field struct {
+	Name   string   // 字段名
+	Path   []string // 从实体开始的字段路径，嵌入的结构体会展开，如 Base.Id
+	Column string
+	Type   ast.Expr
+	File   *ast.File
+
+	PrimaryKey bool
+	ReadOnly   bool
+	InsertOnly bool
+	UpdateOnly bool
+	UseDefault bool
+	OmitEmpty  bool
+}
+
+// entity 为需要生成代码的结构体。
+type entity struct {
+	Name   string
+	Fields []*field
+
+	IsEntity   bool
+	Table      string
+	PrimaryKey string
+
+	// Unsupported 不为空时表示无法准确生成 ScanColumns 和 EncodeFields，记录其原因
+	Unsupported string
+}
+
+type element struct {
+	typeName string
+	path     []string
+}
+
+// parseEntity 按照 Mapper 的规则（广度优先，先出现的列优先）收集结构体映射的字段。
+func (pkg *packageInfo) parseEntity(name, tagName string) (*entity, error) {
+	if pkg.structType(name) == nil {
+		return nil, fmt.Errorf("struct type %s not found in package %s", name, pkg.name)
+	}
+
+	var e = &entity{Name: name}
+	e.IsEntity = pkg.isEntity(name)
+	_, e.Table = pkg.stringMethod(name, "TableName")
+	_, e.PrimaryKey = pkg.stringMethod(name, "PrimaryKey")
+
+	var unsupported = func(format string, args ...any) {
+		if e.Unsupported == "" {
+			e.Unsupported = fmt.Sprintf(format, args...)
+		}
+	}
+
+	var seen = make(map[string]bool)
+	var queue = []element{{typeName: name}}
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+
+		for _, astField := range pkg.structType(current.typeName).Fields.List {
+			var names = make([]string, 0, len(astField.Names))
+			for _, ident := range astField.Names {
+				names = append(names, ident.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, embeddedName(astField.Type))
+			}
+
+			var tag string
+			if astField.Tag != nil {
+				var raw, _ = strconv.Unquote(astField.Tag.Value)
+				tag = reflect.StructTag(raw).Get(tagName)
+			}
+
+			for _, fieldName := range names {
+				if !ast.IsExported(fieldName) || tag == kTagValueDisable {
+					continue
+				}
+				var path = append(append([]string(nil), current.path...), fieldName)
+
+				if tag == "" {
+					var structName, isPointer, isLocal = pkg.localStruct(astField.Type)
+					switch {
+					case isLocal && isPointer:
+						unsupported("field %s is an untagged pointer to struct", strings.Join(path, "."))
+					case isLocal:
+						if len(path) > kMaxEmbedDepth {
+							unsupported("field %s embeds %s recursively", strings.Join(path, "."), structName)
+							continue
+						}
+						queue = append(queue, element{typeName: structName, path: path})
+					case isForeignStruct(astField.Type):
+						unsupported("untagged field %s has type %s declared in another package", strings.Join(path, "."), pkg.exprString(astField.Type))
+					}
+					continue
+				}
+
+				var tagValues = strings.Split(tag, kTagValueSeparator)
+				var column = strings.TrimSpace(tagValues[0])
+				if column == "" || seen[column] {
+					continue
+				}
+
+				var tagMap = make(map[string]bool)
+				for _, tagValue := range tagValues {
+					tagMap[strings.ToLower(strings.TrimSpace(tagValue))] = true
+				}
+
+				switch {
+				case tagMap[kTagValueJSON]:
+					unsupported("field %s uses the %s option", strings.Join(path, "."), kTagValueJSON)
+				case tagMap[kTagValueArray]:
+					unsupported("field %s uses the %s option", strings.Join(path, "."), kTagValueArray)
+				case tagMap[kTagValueSet]:
+					unsupported("field %s uses the %s option", strings.Join(path, "."), kTagValueSet)
+				case tagMap[kTagValuePrefix]:
+					unsupported("field %s uses the %s option", strings.Join(path, "."), kTagValuePrefix)
+					if _, _, isLocal := pkg.localStruct(astField.Type); isLocal {
+						// 关联结构体的列不属于实体
+						continue
+					}
+				}
+				seen[column] = true
+
+				var f = &field{}
+				f.Name = fieldName
+				f.Path = path
+				f.Column = column
+				f.Type = astField.Type
+				f.File = pkg.files[current.typeName]
+				f.UseDefault = tagMap[kTagValueDefault]
+				f.PrimaryKey = tagMap[kTagValuePK]
+				f.ReadOnly = tagMap[kTagValueReadOnly]
+				// 同时设置 insert 和 update 等同于都不设置
+				f.InsertOnly = tagMap[kTagValueInsert] && !tagMap[kTagValueUpdate]
+				f.UpdateOnly = tagMap[kTagValueUpdate] && !tagMap[kTagValueInsert]
+				f.OmitEmpty = tagMap[kTagValueOmitEmpty]
+				e.Fields = append(e.Fields, f)
+			}
+		}
+	}
+	return e, nil
+}
+
+// primaryKeys 返回主键字段，优先使用 pk 标签，没有设置 pk 标签时使用 PrimaryKey() 返回的列。
+func (e *entity) primaryKeys() []*field {
+	var keys []*field
+	for _, f := range e.Fields {
+		if f.PrimaryKey {
+			keys = append(keys, f)
+		}
+	}
+	if len(keys) == 0 && e.PrimaryKey != "" {
+		for _, f := range e.Fields {
+			if f.Column == e.PrimaryKey {
+				keys = append(keys, f)
+			}
+		}
+	}
+	return keys
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	case *ast.IndexExpr:
+		return embeddedName(expr.X)
+	}
+	return ""
+}
+
+// localStruct 判断 expr 是否为当前包中声明的结构体或者结构体指针。
+func (pkg *packageInfo) localStruct(expr ast.Expr) (name string, isPointer bool, ok bool) {
+	if star, isStar := expr.(*ast.StarExpr); isStar {
+		expr = star.X
+		isPointer = true
+	}
+	if ident, isIdent := expr.(*ast.Ident); isIdent && pkg.structType(ident.Name) != nil {
+		return ident.Name, isPointer, true
+	}
+	return "", false, false
+}
+
+// isForeignStruct 判断 expr 是否可能为其它包中声明的结构体，Mapper 会展开这类没有标签的字段，生成代码时无法获知其字段信息。
+func isForeignStruct(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	var selector, ok = expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	// time.Time 没有导出的字段
+	if pkgIdent, ok := selector.X.(*ast.Ident); ok && pkgIdent.Name == "time" && selector.Sel.Name == "Time" {
+		return false
+	}
+	return true
+}
+
+func (pkg *packageInfo) exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, pkg.fset, expr)
+	return buf.String()
+}
+
+// underlying 返回当前包中声明的类型的底层类型，其它包中的类型原样返回。
+func (pkg *packageInfo) underlying(expr ast.Expr) ast.Expr {
+	for depth := 0; depth < 10; depth++ {
+		var ident, ok = expr.(*ast.Ident)
+		if !ok {
+			return expr
+		}
+		var spec = pkg.types[ident.Name]
+		if spec == nil {
+			return expr
+		}
+		expr = spec.Type
+	}
+	return expr
+}
+
+// isNullable 判断字段是否可以直接扫描 NULL，这类字段不需要使用 dbs.Null 包装。
+func (pkg *packageInfo) isNullable(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.InterfaceType:
+		return true
+	case *ast.ArrayType:
+		return expr.Len == nil
+	case *ast.Ident:
+		return expr.Name == "any"
+	}
+	return false
+}
+
+// zeroCheck 返回判断字段是否为零值的表达式，无法根据源码判断时使用 reflect。
+func (pkg *packageInfo) zeroCheck(value string, expr ast.Expr) (string, bool) {
+	switch expr := pkg.underlying(expr).(type) {
+	case *ast.Ident:
+		switch expr.Name {
+		case "string":
+			return value + ` == ""`, false
+		case "bool":
+			return "!" + value, false
+		case "any", "error":
+			return value + " == nil", false
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "complex64", "complex128", "byte", "rune":
+			return value + " == 0", false
+		}
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+		return value + " == nil", false
+	case *ast.ArrayType:
+		if expr.Len == nil {
+			return value + " == nil", false
+		}
+	case *ast.SelectorExpr:
+		if pkgIdent, ok := expr.X.(*ast.Ident); ok && pkgIdent.Name == "time" && expr.Sel.Name == "Time" {
+			return value + ".IsZero()", false
+		}
+	}
+	return "reflect.ValueOf(" + value + ").IsZero()", true
+}
+
+// generate 生成 opts.Types 中的结构体（为空时为实现了 dbs.Entity 接口的结构体）的代码，返回格式化之后的源码以及无法完整生成代码的提示。
+func generate(pkg *packageInfo, opts options) ([]byte, []string, error) {
+	if opts.Tag == "" {
+		opts.Tag = "sql"
+	}
+
+	var names = opts.Types
+	if len(names) == 0 {
+		for _, name := range pkg.order {
+			if pkg.isEntity(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("no struct implementing dbs.Entity found in package %s, use -type to specify", pkg.name)
+	}
+
+	var entities = make([]*entity, 0, len(names))
+	var warnings []string
+	for _, name := range names {
+		var e, err = pkg.parseEntity(strings.TrimSpace(name), opts.Tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if e.Unsupported != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: %s, ScanColumns and EncodeFields are not generated", e.Name, e.Unsupported))
+		}
+		entities = append(entities, e)
+	}
+
+	var w = &writer{pkg: pkg, imports: make(map[string]string)}
+	for _, e := range entities {
+		w.writeEntity(e)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(kGeneratedHeader + "\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg.name)
+	if len(w.imports) > 0 {
+		// 标准库与其它包分为两组
+		var groups [2][]string
+		for path := range w.imports {
+			if isStdImport(path) {
+				groups[0] = append(groups[0], path)
+			} else {
+				groups[1] = append(groups[1], path)
+			}
+		}
+
+		buf.WriteString("import (\n")
+		for idx, group := range groups {
+			if idx > 0 && len(group) > 0 && len(groups[0]) > 0 {
+				buf.WriteString("\n")
+			}
+			sort.Strings(group)
+			for _, path := range group {
+				if alias := w.imports[path]; alias != "" {
+					fmt.Fprintf(&buf, "\t%s %q\n", alias, path)
+				} else {
+					fmt.Fprintf(&buf, "\t%q\n", path)
+				}
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(w.buf.Bytes())
+
+	var src, err = format.Source(buf.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, warnings, nil
+}
+
+type writer struct {
+	pkg     *packageInfo
+	buf     bytes.Buffer
+	imports map[string]string // 导入路径 -> 别名
+}
+
+func (w *writer) printf(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+func (w *writer) writeEntity(e *entity) {
+	var constants = w.writeConstants(e)
+	if e.Unsupported == "" {
+		w.writeScanColumns(e, constants)
+		w.writeEncodeFields(e, constants)
+	}
+	if e.IsEntity {
+		w.writeRepository(e)
+	}
+}
+
+// writeConstants 生成表名以及列名常量，返回字段对应的常量名。
+func (w *writer) writeConstants(e *entity) map[*field]string {
+	var constants = make(map[*field]string, len(e.Fields))
+	var used = make(map[string]bool, len(e.Fields))
+	for _, f := range e.Fields {
+		var name = e.Name + "Column" + f.Name
+		if used[name] {
+			name = e.Name + "Column" + strings.Join(f.Path, "")
+		}
+		used[name] = true
+		constants[f] = name
+	}
+
+	w.printf("// %s 映射的表名以及列名。\n", e.Name)
+	w.printf("const (\n")
+	if e.Table != "" {
+		w.printf("%sTable = %q\n", e.Name, e.Table)
+	}
+	for _, f := range e.Fields {
+		w.printf("%s = %q\n", constants[f], f.Column)
+	}
+	w.printf(")\n\n")
+
+	w.printf("// %sColumns 返回 %s 映射的所有列。\n", e.Name, e.Name)
+	w.printf("func %sColumns() []string {\n", e.Name)
+	w.printf("return []string{\n")
+	for _, f := range e.Fields {
+		w.printf("%s,\n", constants[f])
+	}
+	w.printf("}\n}\n\n")
+	return constants
+}
+
+func receiverName(typeName string) string {
+	for _, r := range typeName {
+		return string(unicode.ToLower(r))
+	}
+	return "e"
+}
+
+func (w *writer) writeScanColumns(e *entity, constants map[*field]string) {
+	var recv = receiverName(e.Name)
+	w.imports[kDBSImport] = ""
+
+	w.printf("// ScanColumns 实现 dbs.ColumnScanner 接口，扫描到 NULL 时将字段设置为零值。\n")
+	w.printf("func (%s *%s) ScanColumns(columns []string) []any {\n", recv, e.Name)
+	w.printf("var targets = make([]any, len(columns))\n")
+	w.printf("for idx, column := range columns {\n")
+	w.printf("switch column {\n")
+	for _, f := range e.Fields {
+		var value = recv + "." + strings.Join(f.Path, ".")
+		w.printf("case %s:\n", constants[f])
+		if w.pkg.isNullable(f.Type) {
+			w.printf("targets[idx] = &%s\n", value)
+		} else {
+			w.printf("targets[idx] = dbs.Null(&%s)\n", value)
+		}
+	}
+	w.printf("}\n}\n")
+	w.printf("return targets\n")
+	w.printf("}\n\n")
+}
+
+func (w *writer) writeEncodeFields(e *entity, constants map[*field]string) {
+	var recv = receiverName(e.Name)
+	w.imports[kDBSImport] = ""
+
+	var fields = make([]*field, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		// 只读字段不参与编码，主键除外（用于生成 WHERE 条件）
+		if f.ReadOnly && !f.PrimaryKey {
+			continue
+		}
+		fields = append(fields, f)
+	}
+
+	w.printf("// EncodeFields 实现 dbs.FieldEncoder 接口。\n")
+	w.printf("func (%s %s) EncodeFields() []dbs.FieldValue {\n", recv, e.Name)
+	w.printf("var values = []dbs.FieldValue{\n")
+	for _, f := range fields {
+		w.printf("{Name: %s, Value: %s.%s", constants[f], recv, strings.Join(f.Path, "."))
+		for _, flag := range []struct {
+			name  string
+			value bool
+		}{
+			{"PrimaryKey", f.PrimaryKey},
+			{"ReadOnly", f.ReadOnly},
+			{"InsertOnly", f.InsertOnly},
+			{"UpdateOnly", f.UpdateOnly},
+		} {
+			if flag.value {
+				w.printf(", %s: true", flag.name)
+			}
+		}
+		w.printf("},\n")
+	}
+	w.printf("}\n")
+
+	for idx, f := range fields {
+		if !f.UseDefault && !f.OmitEmpty {
+			continue
+		}
+		var check, useReflect = w.pkg.zeroCheck(recv+"."+strings.Join(f.Path, "."), f.Type)
+		if useReflect {
+			w.imports["reflect"] = ""
+		}
+		w.printf("if %s {\n", check)
+		if f.UseDefault {
+			w.printf("values[%d].UseDefault = true\n", idx)
+			w.printf("values[%d].Value = dbs.SQL(\"DEFAULT\")\n", idx)
+		}
+		if f.OmitEmpty {
+			w.printf("values[%d].OmitEmpty = true\n", idx)
+		}
+		w.printf("}\n")
+	}
+	w.printf("return values\n")
+	w.printf("}\n\n")
+}
+
+func (w *writer) writeRepository(e *entity) {
+	w.imports[kDBSImport] = ""
+
+	var name = e.Name + "Repository"
+	w.printf("// %s 是 %s 的强类型 Repository。\n", name, e.Name)
+	w.printf("type %s struct {\n", name)
+	w.printf("dbs.Repository[%s]\n", e.Name)
+	w.printf("}\n\n")
+
+	w.printf("// New%s 创建 %s。\n", name, name)
+	w.printf("func New%s(db dbs.Database) *%s {\n", name, name)
+	w.printf("return &%s{Repository: dbs.NewRepository[%s](db)}\n", name, e.Name)
+	w.printf("}\n\n")
+
+	var keys = e.primaryKeys()
+	if len(keys) == 0 {
+		return
+	}
+	w.imports["context"] = ""
+
+	var suffix = make([]string, 0, len(keys))
+	var params = make([]string, 0, len(keys))
+	var args = make([]string, 0, len(keys))
+	for _, key := range keys {
+		var param = paramName(key.Name)
+		suffix = append(suffix, key.Name)
+		params = append(params, param+" "+w.typeString(key))
+		args = append(args, param)
+	}
+	var by = "By" + strings.Join(suffix, "And")
+
+	var id = args[0]
+	if len(args) > 1 {
+		id = "[]any{" + strings.Join(args, ", ") + "}"
+	}
+
+	w.printf("// Find%s 根据主键查询 %s，columns 为空时查询所有列。\n", by, e.Name)
+	w.printf("func (r *%s) Find%s(ctx context.Context, %s, columns string) (*%s, error) {\n", name, by, strings.Join(params, ", "), e.Name)
+	w.printf("return r.Find(ctx, %s, columns)\n", id)
+	w.printf("}\n\n")
+
+	w.printf("// Update%s 根据主键更新 %s。\n", by, e.Name)
+	w.printf("func (r *%s) Update%s(ctx context.Context, %s, values map[string]any) (sql.Result, error) {\n", name, by, strings.Join(params, ", "))
+	w.printf("return r.Update(ctx, %s, values)\n", id)
+	w.printf("}\n\n")
+
+	w.printf("// Delete%s 根据主键删除 %s。\n", by, e.Name)
+	w.printf("func (r *%s) Delete%s(ctx context.Context, %s) (sql.Result, error) {\n", name, by, strings.Join(params, ", "))
+	w.printf("return r.Delete(ctx, %s)\n", id)
+	w.printf("}\n\n")
+	w.imports["database/sql"] = ""
+}
+
+// typeString 返回字段类型的源码，同时记录其中引用的包。
+func (w *writer) typeString(f *field) string {
+	ast.Inspect(f.Type, func(node ast.Node) bool {
+		var selector, ok = node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if pkgIdent, ok := selector.X.(*ast.Ident); ok {
+			if path, alias, found := findImport(f.File, pkgIdent.Name); found {
+				w.imports[path] = alias
+			}
+		}
+		return false
+	})
+	return w.pkg.exprString(f.Type)
+}
+
+// findImport 在 file 的导入声明中查找名为 name 的包。
+func findImport(file *ast.File, name string) (path, alias string, found bool) {
+	if file == nil {
+		return "", "", false
+	}
+	for _, spec := range file.Imports {
+		var importPath, err = strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return importPath, name, true
+			}
+			continue
+		}
+		if guessPackageName(importPath) == name {
+			return importPath, "", true
+		}
+	}
+	return "", "", false
+}
+
+// isStdImport 判断是否为标准库的导入路径。
+func isStdImport(path string) bool {
+	var elem, _, _ = strings.Cut(path, "/")
+	return !strings.Contains(elem, ".")
+}
+
+// guessPackageName 根据导入路径推测包名，如 github.com/jackc/pgx/v5 为 pgx。
+func guessPackageName(path string) string {
+	var elems = strings.Split(path, "/")
+	var name = elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.TrimSuffix(name, "-go")
+	return strings.NewReplacer(".", "_", "-", "_").Replace(name)
+}
+
+var reservedParams = map[string]bool{"r": true, "ctx": true, "columns": true, "values": true, "sql": true, "dbs": true, "context": true}
+
+// paramName 将字段名转换为参数名，如 Id 为 id，UserID 为 userID，URL 为 url。
+func paramName(name string) string {
+	var runes = []rune(name)
+	var upper = 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	switch {
+	case upper == len(runes):
+		upper = len(runes)
+	case upper > 1:
+		upper--
+	}
+	for idx := 0; idx < upper; idx++ {
+		runes[idx] = unicode.ToLower(runes[idx])
+	}
+
+	var param = string(runes)
+	if token.IsKeyword(param) || reservedParams[param] {
+		param = "pk" + name
+	}
+	return param
+}
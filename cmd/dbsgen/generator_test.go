@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func generateSource(t *testing.T, src string, opts options) (string, []string) {
+	t.Helper()
+
+	var fset = token.NewFileSet()
+	var file, err = parser.ParseFile(fset, "model.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := newPackageInfo(fset, []*ast.File{file}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, warnings, err := generate(pkg, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), warnings
+}
+
+// 示例中生成的代码需要与结构体定义保持一致
+func TestGenerate_Stale(t *testing.T) {
+	var dir = filepath.Join("..", "..", "examples", "model")
+	var pkg, err = loadPackage(dir, options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _, err := generate(pkg, options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err := os.ReadFile(filepath.Join(dir, pkg.output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, existing) {
+		t.Fatalf("%s 已过期，需要重新运行 dbsgen", pkg.output)
+	}
+}
+
+const testSource = `package model
+
+import "github.com/google/uuid"
+
+type Base struct {
+	Id    uuid.UUID ` + "`sql:\"id;pk\"`" + `
+	Type  string    ` + "`sql:\"type;pk\"`" + `
+	inner int       ` + "`sql:\"inner\"`" + `
+}
+
+type Order struct {
+	Base
+	Amount  int    ` + "`sql:\"amount;default\"`" + `
+	Type    string ` + "`sql:\"type\"`" + `
+	Ignored string ` + "`sql:\"-\"`" + `
+	Remark  string
+}
+
+func (Order) TableName() string {
+	return "order"
+}
+
+func (Order) PrimaryKey() string {
+	return "id"
+}
+
+type Extra struct {
+	Data map[string]any ` + "`sql:\"data;json\"`" + `
+}
+
+func (*Extra) TableName() string {
+	return "extra"
+}
+
+func (*Extra) PrimaryKey() string {
+	return "id"
+}
+`
+
+func TestGenerate(t *testing.T) {
+	var out, warnings = generateSource(t, testSource, options{})
+	if again, _ := generateSource(t, testSource, options{}); again != out {
+		t.Fatal("期望多次生成的代码一致")
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("期望没有提示, 实际: %v", warnings)
+	}
+
+	for _, expect := range []string{
+		`"github.com/google/uuid"`,
+		`OrderTable        = "order"`,
+		// 外层的 type 列优先，嵌入结构体中的同名列被忽略
+		`OrderColumnType   = "type"`,
+		`targets[idx] = dbs.Null(&o.Base.Id)`,
+		`{Name: OrderColumnId, Value: o.Base.Id, PrimaryKey: true}`,
+		`values[0].Value = dbs.SQL("DEFAULT")`,
+		`func (r *OrderRepository) FindById(ctx context.Context, id uuid.UUID, columns string) (*Order, error)`,
+	} {
+		if !strings.Contains(out, expect) {
+			t.Fatalf("期望包含: %s, 实际:\n%s", expect, out)
+		}
+	}
+	for _, unexpect := range []string{"inner", "Ignored", "Remark", "Extra"} {
+		if strings.Contains(out, unexpect) {
+			t.Fatalf("期望不包含: %s, 实际:\n%s", unexpect, out)
+		}
+	}
+}
+
+func TestGenerate_Unsupported(t *testing.T) {
+	var out, warnings = generateSource(t, testSource, options{Types: []string{"Extra"}})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "json") {
+		t.Fatalf("期望 json 选项的提示, 实际: %v", warnings)
+	}
+	if !strings.Contains(out, `ExtraColumnData = "data"`) {
+		t.Fatalf("期望生成列名常量, 实际:\n%s", out)
+	}
+	// 指针接收者实现的 Entity 无法用于 dbs.Repository[E]
+	for _, unexpect := range []string{"ScanColumns", "EncodeFields", "ExtraRepository"} {
+		if strings.Contains(out, unexpect) {
+			t.Fatalf("期望不包含: %s, 实际:\n%s", unexpect, out)
+		}
+	}
+}
+
+func TestParamName(t *testing.T) {
+	var tests = map[string]string{
+		"Id":     "id",
+		"UserID": "userID",
+		"URL":    "url",
+		"URLKey": "urlKey",
+		"Type":   "pkType",
+		"Values": "pkValues",
+	}
+	for name, expect := range tests {
+		if actual := paramName(name); actual != expect {
+			t.Fatalf("%s 期望: %s, 实际: %s", name, expect, actual)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// dbsgen 根据结构体的 sql 标签以及 dbs.Entity 方法生成代码，包括：
+//
+//   - 表名以及列名常量；
+//   - dbs.ColumnScanner 和 dbs.FieldEncoder 的实现，Mapper 使用它们时不再需要反射；
+//   - 基于 dbs.Repository[E] 的强类型 Repository，如 FindById(ctx, id int64, columns string)。
+//
+// 一般通过 go generate 使用：
+//
+//	//go:generate go run github.com/smartwalle/dbs/cmd/dbsgen -type Mail
+//
+// 参数：
+//
+//	-type    需要生成代码的结构体，多个使用逗号分隔，默认为以值接收者实现了 dbs.Entity 接口的结构体
+//	-tag     标签名，默认为 sql
+//	-output  输出文件，默认为 <包名>_dbs.go
+//	-check   不写入文件，只检查输出文件是否与当前的结构体定义一致，不一致时以非 0 状态码退出
+//
+// 生成的代码只依据结构体标签，不会使用 Mapper 的命名策略（WithNamingStrategy）以及注册的类型转换器（RegisterConverter）。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dbsgen: ")
+
+	var typeNames = flag.String("type", "", "comma-separated list of struct names; defaults to structs implementing dbs.Entity")
+	var tag = flag.String("tag", "sql", "struct tag name")
+	var output = flag.String("output", "", "output file name; defaults to <package>_dbs.go")
+	var check = flag.Bool("check", false, "report whether the output file is stale instead of writing it")
+	flag.Parse()
+
+	var dir = "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var opts = options{Tag: *tag, Output: *output}
+	if *typeNames != "" {
+		opts.Types = strings.Split(*typeNames, ",")
+	}
+
+	var pkg, err = loadPackage(dir, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, warnings, err := generate(pkg, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, warning := range warnings {
+		log.Print(warning)
+	}
+
+	var filename = filepath.Join(dir, pkg.output)
+	if *check {
+		if existing, err := os.ReadFile(filename); err != nil || !bytes.Equal(existing, src) {
+			log.Fatalf("%s is stale, run dbsgen to regenerate it", filename)
+		}
+		return
+	}
+	if err = os.WriteFile(filename, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+package dbs
+
+import "reflect"
+
+type columnOptions struct {
+	alias    string
+	excludes map[string]struct{}
+}
+
+type ColumnOption func(opts *columnOptions)
+
+// WithAlias 为列名添加表别名前缀，如 WithAlias("u") 生成 u.id。
+func WithAlias(alias string) ColumnOption {
+	return func(opts *columnOptions) {
+		opts.alias = alias
+	}
+}
+
+// WithExclude 排除指定的列。
+func WithExclude(columns ...string) ColumnOption {
+	return func(opts *columnOptions) {
+		if opts.excludes == nil {
+			opts.excludes = make(map[string]struct{}, len(columns))
+		}
+		for _, column := range columns {
+			opts.excludes[column] = struct{}{}
+		}
+	}
+}
+
+// Columns 返回结构体 T 映射的列名，使用默认的 Mapper（sql 标签）解析。
+func Columns[T any](opts ...ColumnOption) []string {
+	var columns, _ = ColumnsOf(defaultMapper, (*T)(nil), opts...)
+	return columns
+}
+
+// ColumnsOf 通过 mapper 获取 src 映射的列名，mapper 需要实现 ColumnsMapper 接口，为 nil 时使用默认的 Mapper。
+func ColumnsOf(mapper Mapper, src any, opts ...ColumnOption) ([]string, error) {
+	var cMapper, ok = mapper.(ColumnsMapper)
+	if !ok {
+		cMapper = defaultMapper
+	}
+	var columns, err = cMapper.Columns(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var nOpts = &columnOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(nOpts)
+		}
+	}
+
+	var nColumns = columns[:0]
+	for _, column := range columns {
+		if _, exclude := nOpts.excludes[column]; exclude {
+			continue
+		}
+		if nOpts.alias != "" {
+			column = nOpts.alias + "." + column
+		}
+		nColumns = append(nColumns, column)
+	}
+	return nColumns, nil
+}
+
+// FieldEncoding 为字段与数据库的值之间的转换方式。
+type FieldEncoding uint8
+
+const (
+	// EncodingNone 直接使用字段的值，由 database/sql 进行转换。
+	EncodingNone FieldEncoding = iota
+
+	// EncodingJSON 字段设置了 json 标签选项。
+	EncodingJSON
+
+	// EncodingArray 字段设置了 array 标签选项。
+	EncodingArray
+
+	// EncodingSet 字段设置了 set 标签选项。
+	EncodingSet
+
+	// EncodingConverter 字段的类型注册了转换函数，参考 ConverterMapper。
+	EncodingConverter
+)
+
+// Field 描述结构体字段与列之间的映射关系，不包括通过 prefix 关联的结构体中的字段。
+type Field struct {
+	Column     string
+	Type       reflect.Type
+	Encoding   FieldEncoding
+	UseDefault bool
+	PrimaryKey bool
+	ReadOnly   bool
+	InsertOnly bool
+	UpdateOnly bool
+	OmitEmpty  bool
+	Nullable   bool // 字段可以表示 NULL，如指针、切片、map 以及实现了 sql.Scanner 接口的类型
+}
+
+// Fields 返回结构体 T 映射的字段信息，使用默认的 Mapper（sql 标签）解析。
+func Fields[T any]() []Field {
+	var fields, _ = FieldsOf(defaultMapper, (*T)(nil))
+	return fields
+}
+
+// FieldsOf 通过 mapper 获取 src 映射的字段信息，mapper 需要实现 FieldsMapper 接口，为 nil 时使用默认的 Mapper。
+func FieldsOf(mapper Mapper, src any) ([]Field, error) {
+	var fMapper, ok = mapper.(FieldsMapper)
+	if !ok {
+		fMapper = defaultMapper
+	}
+	return fMapper.Fields(src)
+}
+
+func fieldEncoding(codec fieldCodec) FieldEncoding {
+	switch codec.(type) {
+	case jsonCodec:
+		return EncodingJSON
+	case arrayCodec:
+		return EncodingArray
+	case setCodec:
+		return EncodingSet
+	case converterCodec:
+		return EncodingConverter
+	}
+	return EncodingNone
+}
@@ -0,0 +1,174 @@
+package dbs
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// EncodeFunc 将自定义类型的值转换为数据库驱动支持的值。
+type EncodeFunc func(value any) (driver.Value, error)
+
+// DecodeFunc 将数据库驱动返回的值转换为自定义类型的值，src 不会为 NULL（NULL 对应零值）。
+type DecodeFunc func(src any) (any, error)
+
+// ConverterMapper 是 Mapper 的可选接口，用于为无法实现 driver.Valuer 和 sql.Scanner 的类型（如其它模块中定义的类型）注册转换函数。
+type ConverterMapper interface {
+	RegisterConverter(t reflect.Type, encode EncodeFunc, decode DecodeFunc)
+
+	Converter(t reflect.Type) (encode EncodeFunc, decode DecodeFunc, ok bool)
+}
+
+type converter struct {
+	encode EncodeFunc
+	decode DecodeFunc
+}
+
+// RegisterConverter 注册类型 t 的转换函数，encode 或者 decode 为 nil 时表示该方向不进行转换。
+//
+// Encode、将查询结果映射到结构体字段以及 Builder 绑定参数时都会使用注册的转换函数，t 的指针类型会自动处理。
+func (m *mapper) RegisterConverter(t reflect.Type, encode EncodeFunc, decode DecodeFunc) {
+	if t == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var converters = m.converters.Load().(map[reflect.Type]converter)
+	var nConverters = make(map[reflect.Type]converter, len(converters)+1)
+	for key, value := range converters {
+		nConverters[key] = value
+	}
+	nConverters[t] = converter{encode: encode, decode: decode}
+	m.converters.Store(nConverters)
+
+	// 已经缓存的结构体信息中不包含新注册的转换函数
+	m.structs.Store(make(map[reflect.Type]structMetadata))
+}
+
+func (m *mapper) Converter(t reflect.Type) (EncodeFunc, DecodeFunc, bool) {
+	var c, ok = m.converters.Load().(map[reflect.Type]converter)[t]
+	return c.encode, c.decode, ok
+}
+
+func (m *mapper) converterCodec(fieldType reflect.Type) fieldCodec {
+	var isPointer = fieldType.Kind() == reflect.Ptr
+	if isPointer {
+		fieldType = fieldType.Elem()
+	}
+	var encode, decode, ok = m.Converter(fieldType)
+	if !ok {
+		return nil
+	}
+	return converterCodec{converter: converter{encode: encode, decode: decode}, isPointer: isPointer}
+}
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// converterCodec 用于注册了转换函数的字段。
+type converterCodec struct {
+	converter converter
+	isPointer bool
+}
+
+func (c converterCodec) scanType() reflect.Type {
+	return anyType
+}
+
+func (c converterCodec) encode(column string, value reflect.Value) (any, error) {
+	if c.isPointer {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if c.converter.encode == nil {
+		return value.Interface(), nil
+	}
+	var nValue, err = c.converter.encode(value.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("dbs: encode column %q: %w", column, err)
+	}
+	return nValue, nil
+}
+
+func (c converterCodec) decode(column string, src any, dest reflect.Value) error {
+	var nValue reflect.Value
+	if c.converter.decode == nil {
+		nValue = reflect.ValueOf(src)
+	} else {
+		var value, err = c.converter.decode(src)
+		if err != nil {
+			return fmt.Errorf("dbs: decode column %q: %w", column, err)
+		}
+		nValue = reflect.ValueOf(value)
+	}
+
+	var destType = dest.Type()
+	if c.isPointer {
+		destType = destType.Elem()
+	}
+	if !nValue.IsValid() {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	var value, ok = convertValue(nValue, destType)
+	if !ok {
+		return fmt.Errorf("dbs: decode column %q: cannot assign %s to %s", column, nValue.Type(), destType)
+	}
+	nValue = value
+
+	if c.isPointer {
+		var nPointer = reflect.New(destType)
+		nPointer.Elem().Set(nValue)
+		nValue = nPointer
+	}
+	dest.Set(nValue)
+	return nil
+}
+
+// converterFromSession 返回 Session 使用的 Mapper 注册的转换函数，用于 Builder 绑定参数。
+func converterFromSession(session Session) ConverterMapper {
+	if session == nil {
+		return nil
+	}
+	if raw, ok := session.Mapper().(ConverterMapper); ok {
+		return raw
+	}
+	return nil
+}
+
+// convertArgument 使用 Writer 中的转换函数转换参数，没有对应的转换函数时 converted 为 false。
+func convertArgument(w Writer, arg any) (value any, converted bool, err error) {
+	var raw, ok = w.(*Buffer)
+	if !ok {
+		return arg, false, nil
+	}
+	return encodeArgument(raw.converter, arg)
+}
+
+// encodeArgument 使用 converter 中注册的转换函数转换参数，类型为 *T 且 T 注册了转换函数时，nil 转换为 NULL，否则转换指向的值。
+func encodeArgument(converter ConverterMapper, arg any) (value any, converted bool, err error) {
+	if converter == nil || arg == nil {
+		return arg, false, nil
+	}
+	var argType = reflect.TypeOf(arg)
+	var encode, _, found = converter.Converter(argType)
+	if !found && argType.Kind() == reflect.Ptr {
+		if encode, _, found = converter.Converter(argType.Elem()); found && encode != nil {
+			var pointer = reflect.ValueOf(arg)
+			if pointer.IsNil() {
+				return nil, true, nil
+			}
+			arg = pointer.Elem().Interface()
+		}
+	}
+	if !found || encode == nil {
+		return arg, false, nil
+	}
+	if value, err = encode(arg); err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
@@ -0,0 +1,142 @@
+package dbs_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+)
+
+type Money struct {
+	Cents int64
+}
+
+type OrderStatus int
+
+const (
+	OrderPending OrderStatus = iota
+	OrderPaid
+)
+
+var orderStatusNames = []string{"pending", "paid"}
+
+type converterOrder struct {
+	Id     int64       `sql:"id"`
+	Amount Money       `sql:"amount"`
+	Refund *Money      `sql:"refund"`
+	Status OrderStatus `sql:"status"`
+}
+
+func registerConverters(mapper dbs.Mapper) {
+	var converter = mapper.(dbs.ConverterMapper)
+	converter.RegisterConverter(reflect.TypeOf(Money{}), func(value any) (driver.Value, error) {
+		return value.(Money).Cents, nil
+	}, func(src any) (any, error) {
+		return Money{Cents: src.(int64)}, nil
+	})
+	converter.RegisterConverter(reflect.TypeOf(OrderStatus(0)), func(value any) (driver.Value, error) {
+		return orderStatusNames[value.(OrderStatus)], nil
+	}, func(src any) (any, error) {
+		for idx, name := range orderStatusNames {
+			if name == fmt.Sprintf("%s", src) {
+				return OrderStatus(idx), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown order status %v", src)
+	})
+}
+
+func TestMapper_Converter(t *testing.T) {
+	var mapper = dbs.NewMapper("sql")
+
+	// 注册之前已经缓存的结构体信息需要失效
+	if _, err := mapper.Encode(converterOrder{}); err != nil {
+		t.Fatal(err)
+	}
+	registerConverters(mapper)
+
+	var fieldValues, err = mapper.Encode(converterOrder{Id: 1, Amount: Money{Cents: 100}, Status: OrderPaid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values = make([]any, 0, len(fieldValues))
+	for _, fieldValue := range fieldValues {
+		values = append(values, fieldValue.Value)
+	}
+	if expect := []any{int64(1), int64(100), nil, "paid"}; !reflect.DeepEqual(values, expect) {
+		t.Fatalf("期望值: %v, 实际值: %v", expect, values)
+	}
+}
+
+func TestBuilder_Converter(t *testing.T) {
+	var db = dbs.New(nil)
+	registerConverters(db.Mapper())
+
+	var sb = dbs.NewSelectBuilder()
+	sb.UseSession(db)
+	sb.Selects("id")
+	sb.From("order")
+	sb.Where("amount > ?", Money{Cents: 100})
+	sb.Where("status IN (?)", []OrderStatus{OrderPending, OrderPaid})
+
+	var sql, args, err = sb.SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "SELECT id FROM order WHERE amount > ? AND status IN (?,?)"; sql != expect {
+		t.Fatalf("期望 SQL: %s, 实际 SQL: %s", expect, sql)
+	}
+	if expect := []any{int64(100), "pending", "paid"}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("期望参数: %v, 实际参数: %v", expect, args)
+	}
+
+	// Values 以及 Set 绑定的参数同样需要转换
+	var ib = dbs.NewInsertBuilder().UseSession(db).Table("order").Columns("amount", "status").Values(Money{Cents: 100}, OrderPaid)
+	if sql, args, err = ib.SQL(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []any{int64(100), "paid"}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("期望参数: %v, 实际参数: %v", expect, args)
+	}
+
+	var ub = dbs.NewUpdateBuilder().UseSession(db).Table("order").Set("amount", Money{Cents: 200}).SetValues(map[string]any{"status": OrderPending}).Where("id = ?", 1)
+	if sql, args, err = ub.SQL(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []any{int64(200), "pending", 1}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("期望参数: %v, 实际参数: %v", expect, args)
+	}
+
+	// *T 使用 T 的转换函数，nil 绑定为 NULL
+	var refund = &Money{Cents: 50}
+	var nb = dbs.NewInsertBuilder().UseSession(db).Table("order").Columns("refund", "status").Values(refund, (*OrderStatus)(nil))
+	nb.Suffix("ON CONFLICT (id) DO UPDATE SET refund = ?", (*Money)(nil))
+	if sql, args, err = nb.SQL(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []any{int64(50), nil, nil}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("期望参数: %v, 实际参数: %v", expect, args)
+	}
+	var pb = dbs.NewUpdateBuilder().UseSession(db).Table("order").Set("refund", refund).Where("amount > ?", &Money{Cents: 10})
+	if sql, args, err = pb.SQL(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []any{int64(50), int64(10)}; !reflect.DeepEqual(args, expect) {
+		t.Fatalf("期望参数: %v, 实际参数: %v", expect, args)
+	}
+
+	explain, err := dbs.ExplainSQLWith(db.Mapper(), "amount > ? AND status = ?", []any{Money{Cents: 100}, OrderPaid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "amount > 100 AND status = 'paid'"; explain != expect {
+		t.Fatalf("期望 SQL: %s, 实际 SQL: %s", expect, explain)
+	}
+
+	// 转换函数只属于注册的 Mapper
+	if explain, err = dbs.ExplainSQLWith(dbs.NewMapper("sql"), "status = ?", []any{OrderPaid}); err != nil || explain != "status = 1" {
+		t.Fatalf("期望 SQL: %s, 实际 SQL: %s, 错误: %v", "status = 1", explain, err)
+	}
+}
@@ -0,0 +1,186 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type CreateIndexBuilder struct {
+	dialect     Dialect
+	session     Session
+	name        string
+	table       string
+	columns     []string
+	unique      bool
+	ifNotExists bool
+	where       string
+}
+
+func NewCreateIndexBuilder() *CreateIndexBuilder {
+	var ib = &CreateIndexBuilder{}
+	return ib
+}
+
+func (ib *CreateIndexBuilder) Clone() *CreateIndexBuilder {
+	if ib == nil {
+		return nil
+	}
+	var nib = &CreateIndexBuilder{}
+	nib.dialect = ib.dialect
+	nib.session = ib.session
+	nib.name = ib.name
+	nib.table = ib.table
+	nib.columns = append([]string(nil), ib.columns...)
+	nib.unique = ib.unique
+	nib.ifNotExists = ib.ifNotExists
+	nib.where = ib.where
+	return nib
+}
+
+func (ib *CreateIndexBuilder) Reset() {
+	ib.dialect = nil
+	ib.session = nil
+	ib.name = ""
+	ib.table = ""
+	ib.columns = ib.columns[:0]
+	ib.unique = false
+	ib.ifNotExists = false
+	ib.where = ""
+}
+
+func (ib *CreateIndexBuilder) UseDialect(dialect Dialect) *CreateIndexBuilder {
+	ib.dialect = dialect
+	return ib
+}
+
+func (ib *CreateIndexBuilder) UseSession(session Session) *CreateIndexBuilder {
+	ib.session = session
+	if ib.session != nil {
+		ib.dialect = ib.session.Dialect()
+	}
+	return ib
+}
+
+func (ib *CreateIndexBuilder) Name(name string) *CreateIndexBuilder {
+	ib.name = name
+	return ib
+}
+
+func (ib *CreateIndexBuilder) Table(table string) *CreateIndexBuilder {
+	ib.table = table
+	return ib
+}
+
+// Columns 添加索引的列，可以包含排序或者表达式，如 Columns("user_id", "created_at DESC")。
+func (ib *CreateIndexBuilder) Columns(columns ...string) *CreateIndexBuilder {
+	ib.columns = append(ib.columns, columns...)
+	return ib
+}
+
+func (ib *CreateIndexBuilder) Unique() *CreateIndexBuilder {
+	ib.unique = true
+	return ib
+}
+
+// IfNotExists MySQL 不支持。
+func (ib *CreateIndexBuilder) IfNotExists() *CreateIndexBuilder {
+	ib.ifNotExists = true
+	return ib
+}
+
+// Where 设置部分索引的条件，原样写入 SQL，如 Where("deleted_at IS NULL")，MySQL 不支持。
+func (ib *CreateIndexBuilder) Where(cond string) *CreateIndexBuilder {
+	ib.where = cond
+	return ib
+}
+
+func (ib *CreateIndexBuilder) Validate() error {
+	if len(ib.name) == 0 {
+		return errors.New("dbs: create index clause must specify a name")
+	}
+	if len(ib.table) == 0 {
+		return errors.New("dbs: create index clause must specify a table")
+	}
+	if len(ib.columns) == 0 {
+		return errors.New("dbs: create index clause must specify at least one column")
+	}
+	return nil
+}
+
+func (ib *CreateIndexBuilder) Write(w Writer) (err error) {
+	if err = ib.Validate(); err != nil {
+		return err
+	}
+
+	var syntax = ddlSyntaxOf(ib.dialect)
+	if syntax == ddlMySQL {
+		if ib.ifNotExists {
+			return errors.New("dbs: mysql does not support create index if not exists")
+		}
+		if ib.where != "" {
+			return errors.New("dbs: mysql does not support partial index")
+		}
+	}
+
+	if _, err = w.WriteString("CREATE "); err != nil {
+		return err
+	}
+	if ib.unique {
+		if _, err = w.WriteString("UNIQUE "); err != nil {
+			return err
+		}
+	}
+	if _, err = w.WriteString("INDEX "); err != nil {
+		return err
+	}
+	if ib.ifNotExists {
+		if _, err = w.WriteString("IF NOT EXISTS "); err != nil {
+			return err
+		}
+	}
+	if _, err = w.WriteString(ib.name); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(" ON "); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(ib.table); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(" ("); err != nil {
+		return err
+	}
+	if err = writeNames(w, ib.columns); err != nil {
+		return err
+	}
+	if err = w.WriteByte(')'); err != nil {
+		return err
+	}
+
+	if ib.where != "" {
+		if _, err = w.WriteString(" WHERE "); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(ib.where); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ib *CreateIndexBuilder) SQL() (string, []any, error) {
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	buffer.UseDialect(ib.dialect)
+
+	if err := ib.Write(buffer); err != nil {
+		return "", nil, err
+	}
+	return buffer.String(), buffer.Arguments(), nil
+}
+
+func (ib *CreateIndexBuilder) Exec(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, ib.session, ib)
+}
@@ -0,0 +1,34 @@
+package dbs_test
+
+import (
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
+)
+
+func TestCreateIndexBuilder(t *testing.T) {
+	var ib = dbs.NewCreateIndexBuilder()
+	ib.Name("idx_user_email")
+	ib.Table("user")
+	ib.Columns("email", "created_at DESC")
+	ib.Unique()
+	checkClause(t, ib, "CREATE UNIQUE INDEX idx_user_email ON user (email, created_at DESC)", nil)
+
+	var nib = ib.Clone()
+	nib.IfNotExists()
+	nib.Where("deleted_at IS NULL")
+	if _, _, err := nib.SQL(); err == nil {
+		t.Fatal("MySQL 不支持 IF NOT EXISTS 以及部分索引，应该返回错误")
+	}
+
+	nib.UseDialect(postgres.Dialect())
+	checkClause(t, nib, "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_email ON user (email, created_at DESC) WHERE deleted_at IS NULL", nil)
+
+	var rb = dbs.NewCreateIndexBuilder()
+	if _, _, err := rb.SQL(); err == nil {
+		t.Fatal("没有指定索引名称时应该返回错误")
+	}
+	rb.Reset()
+	rb.Clone().Reset()
+}
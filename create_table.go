@@ -0,0 +1,207 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type CreateTableBuilder struct {
+	dialect     Dialect
+	session     Session
+	table       string
+	ifNotExists bool
+	columns     []ColumnDef
+	primaryKeys []string
+	constraints []string
+	options     *Clauses
+}
+
+func NewCreateTableBuilder() *CreateTableBuilder {
+	var cb = &CreateTableBuilder{}
+	return cb
+}
+
+func (cb *CreateTableBuilder) Clone() *CreateTableBuilder {
+	if cb == nil {
+		return nil
+	}
+	var ncb = &CreateTableBuilder{}
+	ncb.dialect = cb.dialect
+	ncb.session = cb.session
+	ncb.table = cb.table
+	ncb.ifNotExists = cb.ifNotExists
+	ncb.columns = append([]ColumnDef(nil), cb.columns...)
+	ncb.primaryKeys = append([]string(nil), cb.primaryKeys...)
+	ncb.constraints = append([]string(nil), cb.constraints...)
+	ncb.options = cb.options.Clone()
+	return ncb
+}
+
+func (cb *CreateTableBuilder) Reset() {
+	cb.dialect = nil
+	cb.session = nil
+	cb.table = ""
+	cb.ifNotExists = false
+	cb.columns = cb.columns[:0]
+	cb.primaryKeys = cb.primaryKeys[:0]
+	cb.constraints = cb.constraints[:0]
+	cb.options.reset()
+}
+
+func (cb *CreateTableBuilder) UseDialect(dialect Dialect) *CreateTableBuilder {
+	cb.dialect = dialect
+	return cb
+}
+
+func (cb *CreateTableBuilder) UseSession(session Session) *CreateTableBuilder {
+	cb.session = session
+	if cb.session != nil {
+		cb.dialect = cb.session.Dialect()
+	}
+	return cb
+}
+
+func (cb *CreateTableBuilder) Table(table string) *CreateTableBuilder {
+	cb.table = table
+	return cb
+}
+
+func (cb *CreateTableBuilder) IfNotExists() *CreateTableBuilder {
+	cb.ifNotExists = true
+	return cb
+}
+
+// Column 添加列定义，已经存在同名的列时替换原有的定义。
+func (cb *CreateTableBuilder) Column(column ColumnDef) *CreateTableBuilder {
+	for idx := range cb.columns {
+		if cb.columns[idx].Name == column.Name {
+			cb.columns[idx] = column
+			return cb
+		}
+	}
+	cb.columns = append(cb.columns, column)
+	return cb
+}
+
+// PrimaryKey 设置表级主键，如 PrimaryKey("user_id", "role_id")。
+func (cb *CreateTableBuilder) PrimaryKey(columns ...string) *CreateTableBuilder {
+	cb.primaryKeys = columns
+	return cb
+}
+
+// Constraint 添加表级约束，原样写入 SQL，如 Constraint("UNIQUE (email)")、Constraint("CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES user (id)")。
+func (cb *CreateTableBuilder) Constraint(constraint string) *CreateTableBuilder {
+	cb.constraints = append(cb.constraints, constraint)
+	return cb
+}
+
+// Option 添加表选项，写在括号之后，如 Option("ENGINE=InnoDB")、Option("DEFAULT CHARSET=utf8mb4")。
+func (cb *CreateTableBuilder) Option(sql any, args ...any) *CreateTableBuilder {
+	if cb.options == nil {
+		cb.options = NewClauses(' ')
+	}
+	cb.options.Append(sql, args...)
+	return cb
+}
+
+func (cb *CreateTableBuilder) Validate() error {
+	if len(cb.table) == 0 {
+		return errors.New("dbs: create table clause must specify a table")
+	}
+	if len(cb.columns) == 0 {
+		return errors.New("dbs: create table clause must specify at least one column")
+	}
+	return nil
+}
+
+func (cb *CreateTableBuilder) Write(w Writer) (err error) {
+	if err = cb.Validate(); err != nil {
+		return err
+	}
+
+	var syntax = ddlSyntaxOf(cb.dialect)
+
+	if _, err = w.WriteString("CREATE TABLE "); err != nil {
+		return err
+	}
+	if cb.ifNotExists {
+		if _, err = w.WriteString("IF NOT EXISTS "); err != nil {
+			return err
+		}
+	}
+	if _, err = w.WriteString(cb.table); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(" ("); err != nil {
+		return err
+	}
+
+	// SQLite 的自增列已经声明为 INTEGER PRIMARY KEY，不能再添加表级主键
+	var writePrimaryKey = len(cb.primaryKeys) > 0
+	for idx, column := range cb.columns {
+		if idx > 0 {
+			if _, err = w.WriteString(", "); err != nil {
+				return err
+			}
+		}
+		if syntax.rowidColumn(column) {
+			writePrimaryKey = false
+		}
+		if err = writeColumnDef(w, syntax, column); err != nil {
+			return err
+		}
+	}
+
+	if writePrimaryKey {
+		if _, err = w.WriteString(", PRIMARY KEY ("); err != nil {
+			return err
+		}
+		if err = writeNames(w, cb.primaryKeys); err != nil {
+			return err
+		}
+		if err = w.WriteByte(')'); err != nil {
+			return err
+		}
+	}
+
+	for _, constraint := range cb.constraints {
+		if _, err = w.WriteString(", "); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(constraint); err != nil {
+			return err
+		}
+	}
+
+	if err = w.WriteByte(')'); err != nil {
+		return err
+	}
+
+	if cb.options.valid() {
+		if err = w.WriteByte(' '); err != nil {
+			return err
+		}
+		if err = cb.options.Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cb *CreateTableBuilder) SQL() (string, []any, error) {
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	buffer.UseDialect(cb.dialect)
+	buffer.UseConverter(converterFromSession(cb.session))
+
+	if err := cb.Write(buffer); err != nil {
+		return "", nil, err
+	}
+	return buffer.String(), buffer.Arguments(), nil
+}
+
+func (cb *CreateTableBuilder) Exec(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, cb.session, cb)
+}
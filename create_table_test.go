@@ -0,0 +1,128 @@
+package dbs_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/mysql"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+func TestCreateTableBuilder(t *testing.T) {
+	var cb = dbs.NewCreateTableBuilder()
+	cb.Table("user")
+	cb.IfNotExists()
+	cb.Column(dbs.ColumnDef{Name: "id", Type: "BIGINT", AutoIncrement: true})
+	cb.Column(dbs.ColumnDef{Name: "email", Type: "VARCHAR(128)", NotNull: true, Unique: true})
+	cb.Column(dbs.ColumnDef{Name: "status", Type: "INT", NotNull: true, Default: "1"})
+	cb.PrimaryKey("id")
+	cb.Option("ENGINE=InnoDB")
+
+	checkClause(t, cb, "CREATE TABLE IF NOT EXISTS user (id BIGINT NOT NULL AUTO_INCREMENT, email VARCHAR(128) NOT NULL UNIQUE, status INT NOT NULL DEFAULT 1, PRIMARY KEY (id)) ENGINE=InnoDB", nil)
+
+	var ncb = cb.Clone()
+	ncb.Column(dbs.ColumnDef{Name: "status", Type: "SMALLINT", NotNull: true, Default: "0"})
+	ncb.Constraint("CHECK (status >= 0)")
+	checkClause(t, ncb, "CREATE TABLE IF NOT EXISTS user (id BIGINT NOT NULL AUTO_INCREMENT, email VARCHAR(128) NOT NULL UNIQUE, status SMALLINT NOT NULL DEFAULT 0, PRIMARY KEY (id), CHECK (status >= 0)) ENGINE=InnoDB", nil)
+
+	ncb.UseDialect(postgres.Dialect())
+	checkClause(t, ncb, "CREATE TABLE IF NOT EXISTS user (id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY, email VARCHAR(128) NOT NULL UNIQUE, status SMALLINT NOT NULL DEFAULT 0, PRIMARY KEY (id), CHECK (status >= 0)) ENGINE=InnoDB", nil)
+
+	ncb.UseDialect(sqlite.Dialect())
+	checkClause(t, ncb, "CREATE TABLE IF NOT EXISTS user (id INTEGER PRIMARY KEY AUTOINCREMENT, email VARCHAR(128) NOT NULL UNIQUE, status SMALLINT NOT NULL DEFAULT 0, CHECK (status >= 0)) ENGINE=InnoDB", nil)
+
+	var rb = dbs.NewCreateTableBuilder()
+	if _, _, err := rb.SQL(); err == nil {
+		t.Fatal("没有指定表名时应该返回错误")
+	}
+	rb.Table("user")
+	rb.Column(dbs.ColumnDef{Name: "id"})
+	if _, _, err := rb.SQL(); err == nil {
+		t.Fatal("没有指定列类型时应该返回错误")
+	}
+	rb.Reset()
+	rb.Clone().Reset()
+}
+
+type ddlProfile struct {
+	Bio string `sql:"bio"`
+}
+
+type ddlUser struct {
+	Id        int64          `sql:"id;default"`
+	Name      string         `sql:"name"`
+	Status    int8           `sql:"status;default"`
+	Nickname  *string        `sql:"nickname"`
+	Phone     sql.NullString `sql:"phone"`
+	Tags      []string       `sql:"tags;array"`
+	Profile   ddlProfile     `sql:"profile;json"`
+	CreatedAt time.Time      `sql:"created_at;default"`
+}
+
+func (ddlUser) TableName() string {
+	return "ddl_user"
+}
+
+func (ddlUser) PrimaryKey() string {
+	return "id"
+}
+
+type ddlUserRole struct {
+	UserId int64  `sql:"user_id;pk"`
+	RoleId int32  `sql:"role_id;pk;default"`
+	Remark []byte `sql:"remark"`
+}
+
+func (ddlUserRole) TableName() string {
+	return "ddl_user_role"
+}
+
+func (ddlUserRole) PrimaryKey() string {
+	return "user_id"
+}
+
+func TestCreateTableOf(t *testing.T) {
+	var cb, err = dbs.CreateTableOf(nil, ddlUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb.UseDialect(mysql.Dialect())
+	checkClause(t, cb, "CREATE TABLE ddl_user (id BIGINT NOT NULL AUTO_INCREMENT, name VARCHAR(255) NOT NULL, status TINYINT NOT NULL DEFAULT 0, nickname VARCHAR(255), phone VARCHAR(255), tags TEXT, profile JSON NOT NULL, created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (id))", nil)
+
+	cb.UseDialect(postgres.Dialect())
+	checkClause(t, cb, "CREATE TABLE ddl_user (id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY, name TEXT NOT NULL, status SMALLINT NOT NULL DEFAULT 0, nickname TEXT, phone TEXT, tags TEXT[], profile JSONB NOT NULL, created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (id))", nil)
+
+	cb.UseDialect(sqlite.Dialect())
+	checkClause(t, cb, "CREATE TABLE ddl_user (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, status INTEGER NOT NULL DEFAULT 0, nickname TEXT, phone TEXT, tags TEXT, profile TEXT NOT NULL, created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)", nil)
+
+	// 复合主键不会生成自增列
+	if cb, err = dbs.CreateTableOf(nil, ddlUserRole{}); err != nil {
+		t.Fatal(err)
+	}
+	cb.UseDialect(postgres.Dialect())
+	checkClause(t, cb, "CREATE TABLE ddl_user_role (user_id BIGINT NOT NULL, role_id INTEGER NOT NULL DEFAULT 0, remark BYTEA, PRIMARY KEY (user_id, role_id))", nil)
+
+	// PostgreSQL 的标识列只能使用整数类型，无符号的自增列使用 BIGINT
+	if cb, err = dbs.CreateTableOf(nil, ddlOrder{}); err != nil {
+		t.Fatal(err)
+	}
+	cb.UseDialect(postgres.Dialect())
+	checkClause(t, cb, "CREATE TABLE ddl_order (id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY, amount NUMERIC(20) NOT NULL DEFAULT 0, PRIMARY KEY (id))", nil)
+}
+
+type ddlOrder struct {
+	Id     uint64 `sql:"id;default"`
+	Amount uint64 `sql:"amount;default"`
+}
+
+func (ddlOrder) TableName() string {
+	return "ddl_order"
+}
+
+func (ddlOrder) PrimaryKey() string {
+	return "id"
+}
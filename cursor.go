@@ -0,0 +1,200 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Cursor 用于逐行读取查询结果，适用于结果集较大、无法一次性加载到内存中的场景。
+//
+//	var cursor, err = dbs.Iterate[*User](ctx, db, sb)
+//	if err != nil {
+//		return err
+//	}
+//	defer cursor.Close()
+//
+//	for cursor.Next() {
+//		var user = cursor.Value()
+//	}
+//	if err = cursor.Err(); err != nil {
+//		return err
+//	}
+//
+// 读取完所有数据、出现错误或者调用 Close() 后会关闭 *sql.Rows，并调用一次 Logger 的 Trace() 方法记录读取的总行数。
+type Cursor[T any] struct {
+	ctx    context.Context
+	logger Logger
+	mapper Mapper
+	begin  time.Time
+	query  string
+	args   []any
+
+	rows    *sql.Rows
+	columns []*sql.ColumnType
+	strict  bool
+
+	// 使用默认 Mapper 且 T 为结构体时，只在创建 Cursor 时准备一次字段信息
+	names      []string
+	fields     []*fieldMetadata
+	values     []any
+	structType reflect.Type
+
+	value  T
+	total  int
+	err    error
+	closed bool
+}
+
+// kCursorDepth 为直接调用 Iterate()、Next() 或者 Close() 时，Logger 的 Trace() 方法与调用方之间的调用深度。
+const kCursorDepth = 3
+
+// Iterate 执行查询并返回用于逐行读取结果的 Cursor，使用完毕之后需要调用 Close() 方法。
+func Iterate[T any](ctx context.Context, session Session, clause SQLClause) (*Cursor[T], error) {
+	var c = &Cursor[T]{}
+	c.ctx = ctx
+	c.logger = session.Logger()
+	c.mapper = session.Mapper()
+	c.begin = time.Now()
+
+	// 与 Builder 一样，通过 withDepth() 包装的调用需要加上额外的深度
+	var depth = kCursorDepth + depthFromContext(ctx) - kDefaultDepth
+
+	var err error
+	if c.query, c.args, err = clause.SQL(); err != nil {
+		c.err = err
+		c.finish(depth)
+		return nil, err
+	}
+
+	if c.rows, err = session.QueryContext(ctx, c.query, c.args...); err != nil {
+		c.err = err
+		c.finish(depth)
+		return nil, err
+	}
+
+	if err = c.prepare(); err != nil {
+		c.err = err
+		c.finish(depth)
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cursor[T]) prepare() (err error) {
+	var m, ok = c.mapper.(*mapper)
+	if !ok {
+		return nil
+	}
+
+	if c.columns, err = c.rows.ColumnTypes(); err != nil {
+		return err
+	}
+	c.strict = strictFromContext(c.ctx, m.strict)
+
+	var destType = reflect.TypeOf((*T)(nil)).Elem()
+	for destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	if isColumnScanner(destType) {
+		c.names = columnNames(c.columns)
+		return nil
+	}
+	if destType.Kind() == reflect.Struct && !m.scanAsScanner(destType, c.columns) && destType != timeType {
+		c.structType = destType
+		c.fields, c.values, err = m.prepare(destType, c.columns, c.strict)
+	}
+	return err
+}
+
+// Next 读取下一行数据，没有更多数据或者出现错误时返回 false，可以通过 Err() 方法获取错误信息。
+func (c *Cursor[T]) Next() bool {
+	if c.closed {
+		return false
+	}
+
+	var value T
+	var ok, err = c.next(&value)
+	if err != nil || !ok {
+		c.err = err
+		c.finish(kCursorDepth)
+		return false
+	}
+	c.value = value
+	c.total++
+	return true
+}
+
+func (c *Cursor[T]) next(dest *T) (bool, error) {
+	var m, ok = c.mapper.(*mapper)
+	if !ok {
+		// 自定义的 Mapper 在解码单行数据时会自行调用 rows.Next()
+		if _, err := decode(c.ctx, c.mapper, c.rows, dest); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	if !c.rows.Next() {
+		return false, c.rows.Err()
+	}
+
+	var destType, destValue = base(reflect.TypeOf(dest), reflect.ValueOf(dest))
+	if c.names != nil {
+		return true, scanColumns(c.rows, c.names, destValue.Addr().Interface().(ColumnScanner))
+	}
+	if c.structType != nil {
+		return true, scanIntoStruct(c.rows, c.columns, c.fields, c.values, c.strict, c.total, destValue)
+	}
+	return true, m.scanOne(c.rows, c.columns, c.strict, dest, destType, destValue)
+}
+
+// Value 返回 Next() 读取的当前行数据。
+func (c *Cursor[T]) Value() T {
+	return c.value
+}
+
+// Err 返回读取过程中出现的错误。
+func (c *Cursor[T]) Err() error {
+	return c.err
+}
+
+// Total 返回已经读取的行数。
+func (c *Cursor[T]) Total() int {
+	return c.total
+}
+
+// Close 关闭 Cursor，可以在读取完所有数据之前调用，重复调用不会产生影响。
+func (c *Cursor[T]) Close() error {
+	if c.closed {
+		return nil
+	}
+	return c.finish(kCursorDepth)
+}
+
+func (c *Cursor[T]) finish(depth int) (err error) {
+	c.closed = true
+
+	if c.rows != nil {
+		err = c.rows.Close()
+	}
+
+	for idx, value := range c.values {
+		var field = c.fields[idx]
+		if field != nil {
+			field.ValuePool.Put(value)
+		}
+	}
+	c.fields = nil
+	c.values = nil
+
+	if c.logger != nil {
+		c.logger.Trace(c.ctx, depth, c.begin, c.query, c.args, int64(c.total), c.err)
+	}
+	return err
+}
@@ -60,10 +60,12 @@ func Open(driver, url string, maxOpen, maxIdle int) (*DB, error) {
 }
 
 type DB struct {
-	db      unsafe.Pointer
-	dialect Dialect
-	logger  Logger
-	mapper  Mapper
+	db        unsafe.Pointer
+	dialect   Dialect
+	logger    Logger
+	mapper    Mapper
+	strategy  SliceStrategy
+	chunkSize int
 }
 
 func New(db *sql.DB) *DB {
@@ -128,6 +130,24 @@ func (db *DB) UseMapper(mapper Mapper) {
 	}
 }
 
+func (db *DB) SliceStrategy() SliceStrategy {
+	return db.strategy
+}
+
+// UseSliceStrategy 设置切片参数的默认处理策略，Builder 可以通过 UseSliceStrategy() 方法单独指定。
+func (db *DB) UseSliceStrategy(strategy SliceStrategy) {
+	db.strategy = strategy
+}
+
+func (db *DB) SliceChunkSize() int {
+	return db.chunkSize
+}
+
+// UseSliceChunkSize 设置 SliceChunk 策略拆分切片的长度，小于等于 0 时使用默认值 1000，不会超过 Dialect 允许的最大参数数量。
+func (db *DB) UseSliceChunkSize(size int) {
+	db.chunkSize = size
+}
+
 func (db *DB) Session(ctx context.Context) Session {
 	var session, ok = ctx.Value(internal.TxSessionKey{}).(Session)
 	if ok && session != nil {
@@ -168,5 +188,6 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	var nTx = &Tx{}
 	nTx.tx = tx
 	nTx.db = db
+	nTx.ctx = ctx
 	return nTx, nil
 }
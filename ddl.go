@@ -0,0 +1,364 @@
+package dbs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ColumnDef 为 CREATE TABLE 以及 ALTER TABLE 中的列定义。
+type ColumnDef struct {
+	Name string
+
+	// Type 为数据库中的类型，如 VARCHAR(64)、BIGINT，为空时根据 GoType、Encoding 以及 Dialect 推断
+	Type string
+
+	GoType   reflect.Type
+	Encoding FieldEncoding
+
+	NotNull bool
+
+	// Default 为默认值表达式，原样写入 SQL，如 0、'draft'、CURRENT_TIMESTAMP，为空时表示没有默认值
+	Default string
+
+	// AutoIncrement 自增列，MySQL 生成 AUTO_INCREMENT，PostgreSQL 生成 GENERATED BY DEFAULT AS IDENTITY，
+	// SQLite 生成 INTEGER PRIMARY KEY AUTOINCREMENT（该列会作为主键）
+	AutoIncrement bool
+
+	// PrimaryKey 列级主键，复合主键使用 CreateTableBuilder.PrimaryKey()
+	PrimaryKey bool
+
+	Unique bool
+}
+
+// ddlSyntax 为 DDL 语法的分类，根据 DialectName() 区分，无法识别的 Dialect 使用 MySQL 的语法。
+type ddlSyntax uint8
+
+const (
+	ddlMySQL ddlSyntax = iota
+	ddlPostgres
+	ddlSQLite
+)
+
+func ddlSyntaxOf(dialect Dialect) ddlSyntax {
+	switch DialectName(dialect) {
+	case "postgres":
+		return ddlPostgres
+	case "sqlite":
+		return ddlSQLite
+	}
+	return ddlMySQL
+}
+
+// rowidColumn 判断列是否会生成 SQLite 的 INTEGER PRIMARY KEY AUTOINCREMENT。
+func (s ddlSyntax) rowidColumn(column ColumnDef) bool {
+	return s == ddlSQLite && column.AutoIncrement
+}
+
+func (s ddlSyntax) columnType(column ColumnDef) (string, error) {
+	if column.Type != "" {
+		return column.Type, nil
+	}
+	if column.GoType == nil {
+		return "", fmt.Errorf("dbs: column %s must specify a type", column.Name)
+	}
+	var columnType = s.goType(column.GoType, column.Encoding)
+	if s == ddlPostgres && column.AutoIncrement && columnType == "NUMERIC(20)" {
+		// PostgreSQL 的标识列只能使用 SMALLINT、INTEGER 或者 BIGINT
+		columnType = "BIGINT"
+	}
+	return columnType, nil
+}
+
+var (
+	ddlTimeType  = reflect.TypeOf(time.Time{})
+	ddlBytesType = reflect.TypeOf([]byte(nil))
+
+	// ddlNullTypes 为 sql.NullXXX 对应的值类型
+	ddlNullTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+		reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+		reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+		reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+		reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(uint8(0)),
+		reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+		reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+		reflect.TypeOf(sql.NullTime{}):    ddlTimeType,
+	}
+)
+
+// goType 返回 Go 类型对应的列类型，无法识别的类型使用 TEXT。
+func (s ddlSyntax) goType(goType reflect.Type, encoding FieldEncoding) string {
+	for goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	if valueType, ok := ddlNullTypes[goType]; ok {
+		goType = valueType
+	}
+
+	switch encoding {
+	case EncodingJSON:
+		switch s {
+		case ddlMySQL:
+			return "JSON"
+		case ddlPostgres:
+			return "JSONB"
+		}
+		return "TEXT"
+	case EncodingArray:
+		if s == ddlPostgres && goType.Kind() == reflect.Slice {
+			return s.goType(goType.Elem(), EncodingNone) + "[]"
+		}
+		return "TEXT"
+	case EncodingSet:
+		if s == ddlMySQL {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	}
+
+	switch goType {
+	case ddlTimeType:
+		switch s {
+		case ddlMySQL:
+			return "DATETIME"
+		case ddlPostgres:
+			return "TIMESTAMPTZ"
+		}
+		return "DATETIME"
+	case ddlBytesType:
+		if s == ddlPostgres {
+			return "BYTEA"
+		}
+		return "BLOB"
+	}
+
+	var kind = goType.Kind()
+	switch {
+	case kind == reflect.Bool:
+		return "BOOLEAN"
+	case kind >= reflect.Int && kind <= reflect.Uint64:
+		if s == ddlSQLite {
+			// SQLite 只有 INTEGER PRIMARY KEY 才会作为 rowid 的别名
+			return "INTEGER"
+		}
+		return s.integerType(kind)
+	case kind == reflect.Float32:
+		switch s {
+		case ddlMySQL:
+			return "FLOAT"
+		}
+		return "REAL"
+	case kind == reflect.Float64:
+		switch s {
+		case ddlMySQL:
+			return "DOUBLE"
+		case ddlPostgres:
+			return "DOUBLE PRECISION"
+		}
+		return "REAL"
+	case kind == reflect.String:
+		if s == ddlMySQL {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	}
+	return "TEXT"
+}
+
+func (s ddlSyntax) integerType(kind reflect.Kind) string {
+	if s == ddlPostgres {
+		// PostgreSQL 没有无符号整数，使用更大的类型保存
+		switch kind {
+		case reflect.Int8, reflect.Int16, reflect.Uint8:
+			return "SMALLINT"
+		case reflect.Int32, reflect.Uint16:
+			return "INTEGER"
+		case reflect.Uint32, reflect.Int, reflect.Int64:
+			return "BIGINT"
+		}
+		return "NUMERIC(20)"
+	}
+
+	switch kind {
+	case reflect.Int8:
+		return "TINYINT"
+	case reflect.Uint8:
+		return "TINYINT UNSIGNED"
+	case reflect.Int16:
+		return "SMALLINT"
+	case reflect.Uint16:
+		return "SMALLINT UNSIGNED"
+	case reflect.Int32:
+		return "INT"
+	case reflect.Uint32:
+		return "INT UNSIGNED"
+	case reflect.Int, reflect.Int64:
+		return "BIGINT"
+	}
+	return "BIGINT UNSIGNED"
+}
+
+// zeroDefault 返回 Go 类型零值对应的默认值表达式，time.Time 使用 CURRENT_TIMESTAMP，无法推断时返回空字符串。
+func zeroDefault(goType reflect.Type, encoding FieldEncoding) string {
+	if encoding != EncodingNone {
+		return ""
+	}
+	for goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	if valueType, ok := ddlNullTypes[goType]; ok {
+		goType = valueType
+	}
+	if goType == ddlTimeType {
+		return "CURRENT_TIMESTAMP"
+	}
+
+	switch kind := goType.Kind(); {
+	case kind == reflect.Bool:
+		return "FALSE"
+	case kind >= reflect.Int && kind <= reflect.Float64:
+		return "0"
+	case kind == reflect.String:
+		return "''"
+	}
+	return ""
+}
+
+func writeColumnDef(w Writer, syntax ddlSyntax, column ColumnDef) (err error) {
+	if column.Name == "" {
+		return errors.New("dbs: column definition must specify a name")
+	}
+	if _, err = w.WriteString(column.Name); err != nil {
+		return err
+	}
+
+	if syntax.rowidColumn(column) {
+		_, err = w.WriteString(" INTEGER PRIMARY KEY AUTOINCREMENT")
+		return err
+	}
+
+	var columnType string
+	if columnType, err = syntax.columnType(column); err != nil {
+		return err
+	}
+	if err = w.WriteByte(' '); err != nil {
+		return err
+	}
+	if _, err = w.WriteString(columnType); err != nil {
+		return err
+	}
+
+	if column.NotNull || column.AutoIncrement {
+		if _, err = w.WriteString(" NOT NULL"); err != nil {
+			return err
+		}
+	}
+	if column.Default != "" {
+		if _, err = w.WriteString(" DEFAULT "); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(column.Default); err != nil {
+			return err
+		}
+	}
+	if column.AutoIncrement {
+		var keyword = " AUTO_INCREMENT"
+		if syntax == ddlPostgres {
+			keyword = " GENERATED BY DEFAULT AS IDENTITY"
+		}
+		if _, err = w.WriteString(keyword); err != nil {
+			return err
+		}
+	}
+	if column.PrimaryKey {
+		if _, err = w.WriteString(" PRIMARY KEY"); err != nil {
+			return err
+		}
+	}
+	if column.Unique {
+		if _, err = w.WriteString(" UNIQUE"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeNames(w Writer, names []string) (err error) {
+	for idx, name := range names {
+		if idx > 0 {
+			if _, err = w.WriteString(", "); err != nil {
+				return err
+			}
+		}
+		if _, err = w.WriteString(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateTableOf 根据 entity 的 Mapper 元数据生成 CreateTableBuilder，mapper 需要实现 FieldsMapper 接口，为 nil 时使用默认的 Mapper。
+//
+//   - 主键为设置了 pk 标签选项的字段，没有时使用 entity.PrimaryKey()；
+//   - 唯一的整数主键设置了 default 标签选项时作为自增列；
+//   - 其它设置了 default 标签选项的字段使用零值作为默认值（time.Time 使用 CURRENT_TIMESTAMP），无法推断默认值的字段允许为 NULL；
+//   - 可以表示 NULL 的字段（指针、sql.NullXXX 等）以及 readonly 字段允许为 NULL，其它字段为 NOT NULL；
+//   - 列类型根据字段的类型以及 json、array、set 标签选项推断，在调用 UseDialect() 或者 UseSession() 之后写入 SQL 时确定。
+//
+// 返回的 CreateTableBuilder 可以继续修改，如通过 Column() 替换某一列的定义、通过 Option() 添加表选项。
+func CreateTableOf(mapper Mapper, entity Entity) (*CreateTableBuilder, error) {
+	var fields, err = FieldsOf(mapper, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKeys []string
+	for _, field := range fields {
+		if field.PrimaryKey {
+			primaryKeys = append(primaryKeys, field.Column)
+		}
+	}
+	if len(primaryKeys) == 0 && entity.PrimaryKey() != "" {
+		primaryKeys = []string{entity.PrimaryKey()}
+	}
+
+	var isPrimaryKey = func(column string) bool {
+		for _, key := range primaryKeys {
+			if key == column {
+				return true
+			}
+		}
+		return false
+	}
+
+	var cb = NewCreateTableBuilder()
+	cb.Table(entity.TableName())
+	for _, field := range fields {
+		var column = ColumnDef{
+			Name:     field.Column,
+			GoType:   field.Type,
+			Encoding: field.Encoding,
+			NotNull:  !field.Nullable && !field.ReadOnly,
+		}
+
+		var primaryKey = isPrimaryKey(field.Column)
+		if primaryKey {
+			column.NotNull = true
+		}
+
+		if field.UseDefault {
+			var kind = field.Type.Kind()
+			if primaryKey && len(primaryKeys) == 1 && kind >= reflect.Int && kind <= reflect.Uint64 {
+				column.AutoIncrement = true
+			} else if column.Default = zeroDefault(field.Type, field.Encoding); column.Default == "" && !primaryKey {
+				column.NotNull = false
+			}
+		}
+		cb.Column(column)
+	}
+	cb.PrimaryKey(primaryKeys...)
+	return cb, nil
+}
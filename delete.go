@@ -9,6 +9,7 @@ import (
 type DeleteBuilder struct {
 	dialect  Dialect
 	session  Session
+	strategy SliceStrategy
 	prefixes *Clauses
 	options  *Clauses
 	table    string
@@ -23,9 +24,28 @@ func NewDeleteBuilder() *DeleteBuilder {
 	return db
 }
 
+func (db *DeleteBuilder) Clone() *DeleteBuilder {
+	if db == nil {
+		return nil
+	}
+	var ndb = &DeleteBuilder{}
+	ndb.dialect = db.dialect
+	ndb.session = db.session
+	ndb.strategy = db.strategy
+	ndb.prefixes = db.prefixes.Clone()
+	ndb.options = db.options.Clone()
+	ndb.table = db.table
+	ndb.wheres = db.wheres.Clone()
+	ndb.orderBys = db.orderBys.Clone()
+	ndb.limit = clone(db.limit)
+	ndb.suffixes = db.suffixes.Clone()
+	return ndb
+}
+
 func (db *DeleteBuilder) Reset() {
 	db.dialect = nil
 	db.session = nil
+	db.strategy = SliceDefault
 	db.prefixes.reset()
 	db.options.reset()
 	db.table = ""
@@ -48,6 +68,11 @@ func (db *DeleteBuilder) UseSession(session Session) *DeleteBuilder {
 	return db
 }
 
+func (db *DeleteBuilder) UseSliceStrategy(strategy SliceStrategy) *DeleteBuilder {
+	db.strategy = strategy
+	return db
+}
+
 func (db *DeleteBuilder) Prefix(sql any, args ...any) *DeleteBuilder {
 	if db.prefixes == nil {
 		db.prefixes = NewClauses(' ')
@@ -100,13 +125,20 @@ func (db *DeleteBuilder) Suffix(sql any, args ...any) *DeleteBuilder {
 	return db
 }
 
-func (db *DeleteBuilder) Write(w Writer) (err error) {
+func (db *DeleteBuilder) Validate() error {
 	if len(db.table) == 0 {
 		return errors.New("dbs: delete clause must specify a table")
 	}
 	if !db.wheres.valid() {
 		return errors.New("dbs: delete clause must specify a where clause")
 	}
+	return nil
+}
+
+func (db *DeleteBuilder) Write(w Writer) (err error) {
+	if err = db.Validate(); err != nil {
+		return err
+	}
 
 	if db.prefixes.valid() {
 		if err = db.prefixes.Write(w); err != nil {
@@ -178,6 +210,8 @@ func (db *DeleteBuilder) SQL() (string, []any, error) {
 	defer buffer.Release()
 
 	buffer.UseDialect(db.dialect)
+	buffer.UseSliceStrategy(sliceStrategy(db.strategy, db.session))
+	buffer.UseConverter(converterFromSession(db.session))
 
 	if err := db.Write(buffer); err != nil {
 		return "", nil, err
@@ -185,6 +219,10 @@ func (db *DeleteBuilder) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+func (db *DeleteBuilder) chunk(offset int) (string, []any, int, error) {
+	return chunkSQL(db, db.session, db.dialect, sliceStrategy(db.strategy, db.session), offset)
+}
+
 func (db *DeleteBuilder) Scan(ctx context.Context, dest any) error {
 	return scan(ctx, db.session, db, dest)
 }
@@ -13,6 +13,23 @@ func TestDeleteBuilder(t *testing.T) {
 	t.Log(rb.SQL())
 }
 
+func TestDeleteBuilder_Clone(t *testing.T) {
+	var rb = dbs.NewDeleteBuilder()
+	rb.Table("user")
+	rb.Where("status = ?", 1)
+
+	var nrb = rb.Clone()
+	nrb.Where("id = ?", 10)
+	nrb.Limit(1)
+
+	checkClause(t, rb, "DELETE FROM user WHERE status = ?", ExpectArgs(1))
+	checkClause(t, nrb, "DELETE FROM user WHERE status = ? AND id = ? LIMIT ?", ExpectArgs(1, 10, int64(1)))
+
+	var eb = dbs.NewDeleteBuilder()
+	eb.Reset()
+	eb.Clone().Reset()
+}
+
 func BenchmarkDeleteBuilder(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		var rb = dbs.NewDeleteBuilder()
@@ -3,3 +3,82 @@ package dbs
 type Dialect interface {
 	WritePlaceholder(w Writer, idx int) error
 }
+
+// Limiter 是 Dialect 的可选接口，用于声明单条语句允许的最大参数数量和最大字节数，返回 0 表示不限制。
+//
+// InsertBuilder 在拆分多行 VALUES 时会参考该限制。
+type Limiter interface {
+	MaxParameters() int
+
+	MaxBytes() int
+}
+
+func dialectLimits(dialect Dialect) (maxParameters, maxBytes int) {
+	if limiter, ok := dialect.(Limiter); ok {
+		return limiter.MaxParameters(), limiter.MaxBytes()
+	}
+	return 0, 0
+}
+
+// Namer 是 Dialect 的可选接口，用于返回数据库的名称，如 mysql、postgres、sqlite。
+//
+// schema 等需要针对不同数据库生成 SQL 的功能会根据该名称进行区分。
+type Namer interface {
+	Name() string
+}
+
+// DialectName 返回 Dialect 的名称，dialect 没有实现 Namer 接口时返回空字符串。
+func DialectName(dialect Dialect) string {
+	if namer, ok := dialect.(Namer); ok {
+		return namer.Name()
+	}
+	return ""
+}
+
+// Savepointer 是 Dialect 的可选接口，用于生成保存点相关的语句。
+//
+// 没有实现该接口时使用 SAVEPOINT name、ROLLBACK TO SAVEPOINT name 以及 RELEASE SAVEPOINT name，MySQL、PostgreSQL 以及 SQLite 均支持该语法。
+type Savepointer interface {
+	SavepointSQL(name string) string
+
+	RollbackToSQL(name string) string
+
+	// ReleaseSQL 返回空字符串时表示数据库不支持释放保存点，Release() 不会执行任何语句
+	ReleaseSQL(name string) string
+}
+
+type defaultSavepointer struct {
+}
+
+func (defaultSavepointer) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (defaultSavepointer) RollbackToSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (defaultSavepointer) ReleaseSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func dialectSavepointer(dialect Dialect) Savepointer {
+	if savepointer, ok := dialect.(Savepointer); ok {
+		return savepointer
+	}
+	return defaultSavepointer{}
+}
+
+// Defaulter 是 Dialect 的可选接口，用于声明 INSERT 语句的 VALUES 中是否支持 DEFAULT 关键字，没有实现该接口时认为支持。
+//
+// 不支持时（如 SQLite）Repository.CreateInBatches 会按照省略的列对实体分组，每组使用一条 INSERT 语句，省略的列不会写入列名中。
+type Defaulter interface {
+	SupportsDefault() bool
+}
+
+func dialectSupportsDefault(dialect Dialect) bool {
+	if defaulter, ok := dialect.(Defaulter); ok {
+		return defaulter.SupportsDefault()
+	}
+	return true
+}
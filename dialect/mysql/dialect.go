@@ -5,7 +5,14 @@ import "github.com/smartwalle/dbs"
 var _dialect = &dialect{}
 
 const (
+	kName        = "mysql"
 	kPlaceholder = '?'
+
+	// kMaxParameters 预处理语句允许的最大占位符数量
+	kMaxParameters = 65535
+
+	// kMaxBytes 参考 max_allowed_packet 在 MySQL 5.7 中的默认值 4MB
+	kMaxBytes = 4 << 20
 )
 
 func Dialect() dbs.Dialect {
@@ -15,9 +22,21 @@ func Dialect() dbs.Dialect {
 type dialect struct {
 }
 
+func (d *dialect) Name() string {
+	return kName
+}
+
 func (d *dialect) WritePlaceholder(w dbs.Writer, _ int) (err error) {
 	if err = w.WriteByte(kPlaceholder); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (d *dialect) MaxParameters() int {
+	return kMaxParameters
+}
+
+func (d *dialect) MaxBytes() int {
+	return kMaxBytes
+}
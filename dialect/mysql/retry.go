@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// kErrDeadlock 为 ER_LOCK_DEADLOCK，事务因为死锁被回滚
+const kErrDeadlock = 1213
+
+// Retryable 判断 err 是否为死锁错误。
+//
+// 为了不依赖具体的驱动，错误链中包含 Number 字段的错误（如 github.com/go-sql-driver/mysql 的 *MySQLError）使用该字段判断，
+// 其它错误根据 "Error 1213" 开头的错误信息判断。
+func (d *dialect) Retryable(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if number, ok := errorNumber(err); ok {
+			return number == kErrDeadlock
+		}
+		if strings.HasPrefix(err.Error(), "Error "+strconv.Itoa(kErrDeadlock)) {
+			return true
+		}
+	}
+	return false
+}
+
+func errorNumber(err error) (uint64, bool) {
+	var value = reflect.ValueOf(err)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return 0, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	var field = value.FieldByName("Number")
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(field.Int()), true
+	}
+	return 0, false
+}
@@ -9,7 +9,11 @@ import (
 var _dialect = &dialect{}
 
 const (
+	kName        = "postgres"
 	kPlaceholder = '$'
+
+	// kMaxParameters 扩展查询协议中参数数量使用 uint16 表示
+	kMaxParameters = 65535
 )
 
 func Dialect() dbs.Dialect {
@@ -19,6 +23,10 @@ func Dialect() dbs.Dialect {
 type dialect struct {
 }
 
+func (d *dialect) Name() string {
+	return kName
+}
+
 func (d *dialect) WritePlaceholder(w dbs.Writer, idx int) (err error) {
 	if err = w.WriteByte(kPlaceholder); err != nil {
 		return err
@@ -28,3 +36,11 @@ func (d *dialect) WritePlaceholder(w dbs.Writer, idx int) (err error) {
 	}
 	return nil
 }
+
+func (d *dialect) MaxParameters() int {
+	return kMaxParameters
+}
+
+func (d *dialect) MaxBytes() int {
+	return 0
+}
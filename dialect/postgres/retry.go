@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"errors"
+	"reflect"
+)
+
+const (
+	kSerializationFailure = "40001"
+	kDeadlockDetected     = "40P01"
+)
+
+type sqlState interface {
+	SQLState() string
+}
+
+// Retryable 判断 err 是否为序列化冲突或者死锁错误。
+//
+// 为了不依赖具体的驱动，使用错误链中的 SQLState() 方法（github.com/lib/pq 的 *Error、github.com/jackc/pgx 的 *PgError）
+// 或者 Code 字段获取错误码。
+func (d *dialect) Retryable(err error) bool {
+	var code string
+	var state sqlState
+	if errors.As(err, &state) {
+		code = state.SQLState()
+	} else {
+		code = errorCode(err)
+	}
+	return code == kSerializationFailure || code == kDeadlockDetected
+}
+
+func errorCode(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		var value = reflect.ValueOf(err)
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			continue
+		}
+		if field := value.FieldByName("Code"); field.Kind() == reflect.String {
+			return field.String()
+		}
+	}
+	return ""
+}
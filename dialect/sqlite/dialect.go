@@ -0,0 +1,47 @@
+package sqlite
+
+import "github.com/smartwalle/dbs"
+
+var _dialect = &dialect{}
+
+const (
+	kName        = "sqlite"
+	kPlaceholder = '?'
+
+	// kMaxParameters 参考 SQLITE_MAX_VARIABLE_NUMBER 在 SQLite 3.32.0 之后的默认值
+	kMaxParameters = 32766
+
+	// kMaxBytes 参考 SQLITE_MAX_SQL_LENGTH 的默认值
+	kMaxBytes = 1000000000
+)
+
+func Dialect() dbs.Dialect {
+	return _dialect
+}
+
+type dialect struct {
+}
+
+func (d *dialect) Name() string {
+	return kName
+}
+
+func (d *dialect) WritePlaceholder(w dbs.Writer, _ int) (err error) {
+	if err = w.WriteByte(kPlaceholder); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *dialect) MaxParameters() int {
+	return kMaxParameters
+}
+
+func (d *dialect) MaxBytes() int {
+	return kMaxBytes
+}
+
+// SupportsDefault SQLite 不支持在 VALUES 中使用 DEFAULT 关键字。
+func (d *dialect) SupportsDefault() bool {
+	return false
+}
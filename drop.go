@@ -0,0 +1,165 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// DropBuilder 用于生成 DROP TABLE 以及 DROP INDEX 语句。
+type DropBuilder struct {
+	dialect  Dialect
+	session  Session
+	tables   []string
+	index    string
+	table    string
+	ifExists bool
+	cascade  bool
+}
+
+func NewDropBuilder() *DropBuilder {
+	var db = &DropBuilder{}
+	return db
+}
+
+func (db *DropBuilder) Clone() *DropBuilder {
+	if db == nil {
+		return nil
+	}
+	var ndb = &DropBuilder{}
+	ndb.dialect = db.dialect
+	ndb.session = db.session
+	ndb.tables = append([]string(nil), db.tables...)
+	ndb.index = db.index
+	ndb.table = db.table
+	ndb.ifExists = db.ifExists
+	ndb.cascade = db.cascade
+	return ndb
+}
+
+func (db *DropBuilder) Reset() {
+	db.dialect = nil
+	db.session = nil
+	db.tables = db.tables[:0]
+	db.index = ""
+	db.table = ""
+	db.ifExists = false
+	db.cascade = false
+}
+
+func (db *DropBuilder) UseDialect(dialect Dialect) *DropBuilder {
+	db.dialect = dialect
+	return db
+}
+
+func (db *DropBuilder) UseSession(session Session) *DropBuilder {
+	db.session = session
+	if db.session != nil {
+		db.dialect = db.session.Dialect()
+	}
+	return db
+}
+
+// Table 删除表，与 Index 不能同时使用。
+func (db *DropBuilder) Table(tables ...string) *DropBuilder {
+	db.tables = append(db.tables, tables...)
+	return db
+}
+
+// Index 删除索引，MySQL 需要指定索引所在的表，其它数据库会忽略 table。
+func (db *DropBuilder) Index(name, table string) *DropBuilder {
+	db.index = name
+	db.table = table
+	return db
+}
+
+func (db *DropBuilder) IfExists() *DropBuilder {
+	db.ifExists = true
+	return db
+}
+
+// Cascade 同时删除依赖的对象，只有 PostgreSQL 支持，其它数据库会忽略。
+func (db *DropBuilder) Cascade() *DropBuilder {
+	db.cascade = true
+	return db
+}
+
+func (db *DropBuilder) Validate() error {
+	if len(db.tables) == 0 && len(db.index) == 0 {
+		return errors.New("dbs: drop clause must specify a table or an index")
+	}
+	if len(db.tables) > 0 && len(db.index) > 0 {
+		return errors.New("dbs: drop clause can not specify both tables and an index")
+	}
+	return nil
+}
+
+func (db *DropBuilder) Write(w Writer) (err error) {
+	if err = db.Validate(); err != nil {
+		return err
+	}
+
+	var syntax = ddlSyntaxOf(db.dialect)
+
+	var names = db.tables
+	var keyword = "DROP TABLE "
+	if len(db.index) > 0 {
+		if syntax == ddlMySQL {
+			if len(db.table) == 0 {
+				return errors.New("dbs: mysql drop index clause must specify a table")
+			}
+			if db.ifExists {
+				return errors.New("dbs: mysql does not support drop index if exists")
+			}
+		}
+		names = []string{db.index}
+		keyword = "DROP INDEX "
+	} else if syntax == ddlSQLite && len(db.tables) > 1 {
+		return errors.New("dbs: sqlite drop clause supports only one table")
+	}
+
+	if _, err = w.WriteString(keyword); err != nil {
+		return err
+	}
+
+	if db.ifExists {
+		if _, err = w.WriteString("IF EXISTS "); err != nil {
+			return err
+		}
+	}
+	if err = writeNames(w, names); err != nil {
+		return err
+	}
+
+	if len(db.index) > 0 && syntax == ddlMySQL {
+		if _, err = w.WriteString(" ON "); err != nil {
+			return err
+		}
+		if _, err = w.WriteString(db.table); err != nil {
+			return err
+		}
+	}
+
+	if db.cascade && syntax == ddlPostgres {
+		if _, err = w.WriteString(" CASCADE"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DropBuilder) SQL() (string, []any, error) {
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	buffer.UseDialect(db.dialect)
+
+	if err := db.Write(buffer); err != nil {
+		return "", nil, err
+	}
+	return buffer.String(), buffer.Arguments(), nil
+}
+
+func (db *DropBuilder) Exec(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, db.session, db)
+}
@@ -0,0 +1,41 @@
+package dbs_test
+
+import (
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+func TestDropBuilder(t *testing.T) {
+	var db = dbs.NewDropBuilder()
+	db.Table("user", "user_role")
+	db.IfExists()
+	db.Cascade()
+	checkClause(t, db, "DROP TABLE IF EXISTS user, user_role", nil)
+
+	db.UseDialect(postgres.Dialect())
+	checkClause(t, db, "DROP TABLE IF EXISTS user, user_role CASCADE", nil)
+
+	db.UseDialect(sqlite.Dialect())
+	if _, _, err := db.SQL(); err == nil {
+		t.Fatal("SQLite 不支持同时删除多个表，应该返回错误")
+	}
+
+	var ib = dbs.NewDropBuilder()
+	ib.Index("idx_user_email", "user")
+	checkClause(t, ib, "DROP INDEX idx_user_email ON user", nil)
+
+	var nib = ib.Clone()
+	nib.IfExists()
+	nib.UseDialect(postgres.Dialect())
+	checkClause(t, nib, "DROP INDEX IF EXISTS idx_user_email", nil)
+
+	nib.Table("user")
+	if _, _, err := nib.SQL(); err == nil {
+		t.Fatal("同时指定表和索引时应该返回错误")
+	}
+	nib.Reset()
+	nib.Clone().Reset()
+}
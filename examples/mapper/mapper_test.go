@@ -296,6 +296,41 @@ func scanIntoMapSlice[T any](t Tester, enableLog bool) {
 	}
 }
 
+type MailReply struct {
+	Mail
+	Reply *Mail `sql:"reply;prefix"`
+}
+
+func Test_Relation(t *testing.T) {
+	var sb = dbs.NewSelectBuilder()
+	sb.UseSession(db)
+	sb.Selects("m.id", "m.email", "r.id AS reply__id", "r.email AS reply__email")
+	sb.Table("mail AS m")
+	sb.Join("LEFT JOIN mail AS r ON r.id = m.id + 1")
+	sb.Where("m.id < ?", 5)
+	sb.OrderBy("m.id ASC")
+
+	var mails []*MailReply
+	if err := sb.Scan(context.Background(), &mails); err != nil {
+		t.Fatal(err)
+	}
+	for _, mail := range mails {
+		t.Logf("%+v, Reply: %+v \n", mail.Mail, mail.Reply)
+	}
+}
+
+func Test_Strict(t *testing.T) {
+	var ctx = dbs.ContextWithStrict(context.Background(), true)
+
+	_, err := dbs.Query[[]*Mail](ctx, db, "SELECT id, email, 1 AS unknown FROM mail WHERE id < 5 ORDER BY id ASC")
+
+	var decodeErr *dbs.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *dbs.DecodeError, got %v", err)
+	}
+	t.Logf("unknown columns: %v, unpopulated fields: %v \n", decodeErr.UnknownColumns, decodeErr.UnsetFields)
+}
+
 type Tester interface {
 	Fatal(args ...any)
 	Logf(format string, args ...any)
@@ -324,3 +359,117 @@ func Benchmark_MapSlice(b *testing.B) {
 		scanIntoMapSlice[interface{}](b, false)
 	}
 }
+
+func Test_Scanner(t *testing.T) {
+	t.Log("-----Scanner-----")
+	scanScanner[time.Time](t, "SELECT updated_at FROM mail WHERE id = 1")
+	scanScanner[*time.Time](t, "SELECT updated_at FROM mail WHERE id = 1")
+	scanScanner[sql.NullString](t, "SELECT email FROM mail WHERE id = 1")
+	scanScanner[*sql.NullString](t, "SELECT email FROM mail WHERE id = 1")
+	scanScanner[Extra](t, "SELECT extra FROM mail WHERE id = 1")
+
+	scanScanner[[]time.Time](t, "SELECT updated_at FROM mail WHERE id < 5 ORDER BY id ASC")
+	scanScanner[[]*time.Time](t, "SELECT updated_at FROM mail WHERE id < 5 ORDER BY id ASC")
+	scanScanner[[]sql.NullString](t, "SELECT email FROM mail WHERE id < 5 ORDER BY id ASC")
+	scanScanner[[]*sql.NullString](t, "SELECT email FROM mail WHERE id < 5 ORDER BY id ASC")
+	scanScanner[[]sql.NullTime](t, "SELECT created_at FROM mail WHERE id < 5 ORDER BY id ASC")
+	scanScanner[[]Extra](t, "SELECT extra FROM mail WHERE id < 5 AND extra IS NOT NULL ORDER BY id ASC")
+}
+
+func scanScanner[T any](t Tester, query string) {
+	value, err := dbs.Query[T](context.Background(), db, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%T: %+v \n", value, value)
+}
+
+func Test_KeyedMap(t *testing.T) {
+	var ctx = context.Background()
+
+	mails, err := dbs.Query[map[int32]*Mail](ctx, db, "SELECT * FROM mail WHERE id < 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%T: %+v \n", mails, mails)
+
+	groups, err := dbs.Query[map[string][]Mail](dbs.ContextWithMapKey(ctx, "status", dbs.DuplicateError), db, "SELECT * FROM mail WHERE id < 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%T: %+v \n", groups, groups)
+
+	var latest map[string]Mail
+	var sb = dbs.NewSelectBuilder()
+	sb.UseSession(db)
+	sb.Selects("*")
+	sb.From("mail")
+	sb.OrderBy("id ASC")
+	if err = sb.Scan(dbs.ContextWithMapKey(ctx, "status", dbs.DuplicateKeepLast), &latest); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%T: %+v \n", latest, latest)
+}
+
+func Test_Iterate(t *testing.T) {
+	var sb = dbs.NewSelectBuilder()
+	sb.Selects("*")
+	sb.From("mail")
+	sb.OrderBy("id ASC")
+
+	cursor, err := dbs.Iterate[*Mail](context.Background(), db, sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		var mail = cursor.Value()
+		t.Logf("%+v \n", mail)
+
+		// 提前结束读取
+		if cursor.Total() >= 3 {
+			break
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Profile struct {
+	Age  int    `json:"age"`
+	City string `json:"city"`
+	Name string `json:"name"`
+}
+
+type MailJSON struct {
+	Base
+	Email   string   `sql:"email"`
+	Profile *Profile `sql:"extra;json"`
+}
+
+func (MailJSON) TableName() string {
+	return "mail"
+}
+
+func (MailJSON) PrimaryKey() string {
+	return "id"
+}
+
+func Test_JSON(t *testing.T) {
+	var repo = dbs.NewRepository[MailJSON](db)
+
+	var mail = &MailJSON{Email: "json@qq.com", Profile: &Profile{Age: 18, City: "cd", Name: "json"}}
+	if _, err := repo.Create(context.Background(), mail); err != nil {
+		t.Fatal(err)
+	}
+
+	mails, err := repo.FindList(context.Background(), "id, email, extra", "email = ?", "json@qq.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mail := range mails {
+		t.Logf("%+v %+v \n", mail, mail.Profile)
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+//go:generate go run github.com/smartwalle/dbs/cmd/dbsgen
+
+type Base struct {
+	Id int64 `sql:"id;default"`
+}
+
+type User struct {
+	Base
+	Name      string         `sql:"name"`
+	Email     string         `sql:"email;omitempty"`
+	Nickname  sql.NullString `sql:"nickname"`
+	Age       int            `sql:"age"`
+	CreatedAt time.Time      `sql:"created_at;insert"`
+	UpdatedAt *time.Time     `sql:"updated_at"`
+	Version   int            `sql:"version;readonly"`
+}
+
+func (User) TableName() string {
+	return "user"
+}
+
+func (User) PrimaryKey() string {
+	return "id"
+}
+
+type UserRole struct {
+	UserId int64  `sql:"user_id;pk"`
+	RoleId int64  `sql:"role_id;pk"`
+	Note   string `sql:"note"`
+}
+
+func (UserRole) TableName() string {
+	return "user_role"
+}
+
+func (UserRole) PrimaryKey() string {
+	return "user_id"
+}
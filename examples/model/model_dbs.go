@@ -0,0 +1,177 @@
+// Code generated by dbsgen. DO NOT EDIT.
+
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/smartwalle/dbs"
+)
+
+// User 映射的表名以及列名。
+const (
+	UserTable           = "user"
+	UserColumnName      = "name"
+	UserColumnEmail     = "email"
+	UserColumnNickname  = "nickname"
+	UserColumnAge       = "age"
+	UserColumnCreatedAt = "created_at"
+	UserColumnUpdatedAt = "updated_at"
+	UserColumnVersion   = "version"
+	UserColumnId        = "id"
+)
+
+// UserColumns 返回 User 映射的所有列。
+func UserColumns() []string {
+	return []string{
+		UserColumnName,
+		UserColumnEmail,
+		UserColumnNickname,
+		UserColumnAge,
+		UserColumnCreatedAt,
+		UserColumnUpdatedAt,
+		UserColumnVersion,
+		UserColumnId,
+	}
+}
+
+// ScanColumns 实现 dbs.ColumnScanner 接口，扫描到 NULL 时将字段设置为零值。
+func (u *User) ScanColumns(columns []string) []any {
+	var targets = make([]any, len(columns))
+	for idx, column := range columns {
+		switch column {
+		case UserColumnName:
+			targets[idx] = dbs.Null(&u.Name)
+		case UserColumnEmail:
+			targets[idx] = dbs.Null(&u.Email)
+		case UserColumnNickname:
+			targets[idx] = dbs.Null(&u.Nickname)
+		case UserColumnAge:
+			targets[idx] = dbs.Null(&u.Age)
+		case UserColumnCreatedAt:
+			targets[idx] = dbs.Null(&u.CreatedAt)
+		case UserColumnUpdatedAt:
+			targets[idx] = &u.UpdatedAt
+		case UserColumnVersion:
+			targets[idx] = dbs.Null(&u.Version)
+		case UserColumnId:
+			targets[idx] = dbs.Null(&u.Base.Id)
+		}
+	}
+	return targets
+}
+
+// EncodeFields 实现 dbs.FieldEncoder 接口。
+func (u User) EncodeFields() []dbs.FieldValue {
+	var values = []dbs.FieldValue{
+		{Name: UserColumnName, Value: u.Name},
+		{Name: UserColumnEmail, Value: u.Email},
+		{Name: UserColumnNickname, Value: u.Nickname},
+		{Name: UserColumnAge, Value: u.Age},
+		{Name: UserColumnCreatedAt, Value: u.CreatedAt, InsertOnly: true},
+		{Name: UserColumnUpdatedAt, Value: u.UpdatedAt},
+		{Name: UserColumnId, Value: u.Base.Id},
+	}
+	if u.Email == "" {
+		values[1].OmitEmpty = true
+	}
+	if u.Base.Id == 0 {
+		values[6].UseDefault = true
+		values[6].Value = dbs.SQL("DEFAULT")
+	}
+	return values
+}
+
+// UserRepository 是 User 的强类型 Repository。
+type UserRepository struct {
+	dbs.Repository[User]
+}
+
+// NewUserRepository 创建 UserRepository。
+func NewUserRepository(db dbs.Database) *UserRepository {
+	return &UserRepository{Repository: dbs.NewRepository[User](db)}
+}
+
+// FindById 根据主键查询 User，columns 为空时查询所有列。
+func (r *UserRepository) FindById(ctx context.Context, id int64, columns string) (*User, error) {
+	return r.Find(ctx, id, columns)
+}
+
+// UpdateById 根据主键更新 User。
+func (r *UserRepository) UpdateById(ctx context.Context, id int64, values map[string]any) (sql.Result, error) {
+	return r.Update(ctx, id, values)
+}
+
+// DeleteById 根据主键删除 User。
+func (r *UserRepository) DeleteById(ctx context.Context, id int64) (sql.Result, error) {
+	return r.Delete(ctx, id)
+}
+
+// UserRole 映射的表名以及列名。
+const (
+	UserRoleTable        = "user_role"
+	UserRoleColumnUserId = "user_id"
+	UserRoleColumnRoleId = "role_id"
+	UserRoleColumnNote   = "note"
+)
+
+// UserRoleColumns 返回 UserRole 映射的所有列。
+func UserRoleColumns() []string {
+	return []string{
+		UserRoleColumnUserId,
+		UserRoleColumnRoleId,
+		UserRoleColumnNote,
+	}
+}
+
+// ScanColumns 实现 dbs.ColumnScanner 接口，扫描到 NULL 时将字段设置为零值。
+func (u *UserRole) ScanColumns(columns []string) []any {
+	var targets = make([]any, len(columns))
+	for idx, column := range columns {
+		switch column {
+		case UserRoleColumnUserId:
+			targets[idx] = dbs.Null(&u.UserId)
+		case UserRoleColumnRoleId:
+			targets[idx] = dbs.Null(&u.RoleId)
+		case UserRoleColumnNote:
+			targets[idx] = dbs.Null(&u.Note)
+		}
+	}
+	return targets
+}
+
+// EncodeFields 实现 dbs.FieldEncoder 接口。
+func (u UserRole) EncodeFields() []dbs.FieldValue {
+	var values = []dbs.FieldValue{
+		{Name: UserRoleColumnUserId, Value: u.UserId, PrimaryKey: true},
+		{Name: UserRoleColumnRoleId, Value: u.RoleId, PrimaryKey: true},
+		{Name: UserRoleColumnNote, Value: u.Note},
+	}
+	return values
+}
+
+// UserRoleRepository 是 UserRole 的强类型 Repository。
+type UserRoleRepository struct {
+	dbs.Repository[UserRole]
+}
+
+// NewUserRoleRepository 创建 UserRoleRepository。
+func NewUserRoleRepository(db dbs.Database) *UserRoleRepository {
+	return &UserRoleRepository{Repository: dbs.NewRepository[UserRole](db)}
+}
+
+// FindByUserIdAndRoleId 根据主键查询 UserRole，columns 为空时查询所有列。
+func (r *UserRoleRepository) FindByUserIdAndRoleId(ctx context.Context, userId int64, roleId int64, columns string) (*UserRole, error) {
+	return r.Find(ctx, []any{userId, roleId}, columns)
+}
+
+// UpdateByUserIdAndRoleId 根据主键更新 UserRole。
+func (r *UserRoleRepository) UpdateByUserIdAndRoleId(ctx context.Context, userId int64, roleId int64, values map[string]any) (sql.Result, error) {
+	return r.Update(ctx, []any{userId, roleId}, values)
+}
+
+// DeleteByUserIdAndRoleId 根据主键删除 UserRole。
+func (r *UserRoleRepository) DeleteByUserIdAndRoleId(ctx context.Context, userId int64, roleId int64) (sql.Result, error) {
+	return r.Delete(ctx, []any{userId, roleId})
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+)
+
+// plainUser 与 User 拥有相同的字段，但是没有生成的方法，Mapper 会使用反射处理。
+type plainUser User
+
+func TestUser_EncodeFields(t *testing.T) {
+	var mapper = dbs.NewMapper("sql")
+
+	for _, user := range []User{
+		{},
+		{Base: Base{Id: 1}, Name: "a", Email: "b", Age: 2, CreatedAt: time.Now(), Version: 3},
+	} {
+		var fastValues, err = mapper.Encode(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reflectValues, err := mapper.Encode(plainUser(user))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fastValues, reflectValues) {
+			t.Fatalf("期望: %+v, 实际: %+v", reflectValues, fastValues)
+		}
+	}
+}
+
+func TestUser_Columns(t *testing.T) {
+	var columns, err = dbs.NewMapper("sql").Columns(plainUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(UserColumns(), columns) {
+		t.Fatalf("期望: %v, 实际: %v", columns, UserColumns())
+	}
+}
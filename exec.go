@@ -23,7 +23,7 @@ func Query[T any](ctx context.Context, session Session, query string, args ...an
 	}
 	defer rows.Close()
 
-	if rowsAffected, err = session.Mapper().Decode(rows, &dest); err != nil && !errors.Is(err, sql.ErrNoRows) {
+	if rowsAffected, err = decode(ctx, session.Mapper(), rows, &dest); err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return dest, err
 	}
 	return dest, nil
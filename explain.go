@@ -27,17 +27,32 @@ func ExplainToBuffer(buffer *bytes.Buffer, clause SQLClause) (err error) {
 	return ExplainSQLToBuffer(buffer, sql, args)
 }
 
+// ExplainSQL 将 args 代入 sql 的占位符中，生成用于调试的 SQL 语句，支持 ? 以及 $N（PostgreSQL）占位符。
 func ExplainSQL(sql string, args []any) (string, error) {
+	return ExplainSQLWith(nil, sql, args)
+}
+
+func ExplainSQLToBuffer(buffer *bytes.Buffer, sql string, args []any) (err error) {
+	return explainSQL(buffer, nil, sql, args)
+}
+
+// ExplainSQLWith 与 ExplainSQL 相同，使用 mapper 中注册的转换函数（参考 ConverterMapper）渲染参数。
+//
+// Builder 生成的参数已经使用 Session 的 Mapper 转换，Explain(clause) 不需要指定 mapper。
+func ExplainSQLWith(mapper Mapper, sql string, args []any) (string, error) {
+	var converter, _ = mapper.(ConverterMapper)
 	var buffer = bytes.NewBuffer(make([]byte, 0, kDefaultBufferSize))
-	if err := ExplainSQLToBuffer(buffer, sql, args); err != nil {
+	if err := explainSQL(buffer, converter, sql, args); err != nil {
 		return "", err
 	}
 	return buffer.String(), nil
 }
 
-func ExplainSQLToBuffer(buffer *bytes.Buffer, sql string, args []any) (err error) {
-	for len(sql) > 0 && len(args) > 0 {
-		var pos = strings.IndexByte(sql, '?')
+// explainSQL 依次使用 args 替换 ? 占位符，$N 占位符（PostgreSQL）使用第 N 个参数替换，没有对应参数的占位符原样保留。
+func explainSQL(buffer *bytes.Buffer, converter ConverterMapper, sql string, args []any) (err error) {
+	var next int
+	for len(args) > 0 {
+		var pos = strings.IndexAny(sql, "?$")
 		if pos == -1 {
 			break
 		}
@@ -46,12 +61,32 @@ func ExplainSQLToBuffer(buffer *bytes.Buffer, sql string, args []any) (err error
 			return err
 		}
 
-		if err = explainArgument(buffer, args[0]); err != nil {
-			return err
+		var arg any
+		var found bool
+		var end = pos + 1
+		if sql[pos] == '?' {
+			if found = next < len(args); found {
+				arg = args[next]
+				next++
+			}
+		} else {
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+			if idx, err := strconv.Atoi(sql[pos+1 : end]); err == nil && idx >= 1 && idx <= len(args) {
+				arg, found = args[idx-1], true
+			}
 		}
 
-		args = args[1:]
-		sql = sql[pos+1:]
+		if found {
+			err = explainArgument(buffer, converter, arg)
+		} else {
+			_, err = buffer.WriteString(sql[pos:end])
+		}
+		if err != nil {
+			return err
+		}
+		sql = sql[end:]
 	}
 	if len(sql) > 0 {
 		if _, err = buffer.WriteString(sql); err != nil {
@@ -61,7 +96,7 @@ func ExplainSQLToBuffer(buffer *bytes.Buffer, sql string, args []any) (err error
 	return nil
 }
 
-func explainArgument(buffer *bytes.Buffer, arg any) (err error) {
+func explainArgument(buffer *bytes.Buffer, converter ConverterMapper, arg any) (err error) {
 	switch raw := arg.(type) {
 	case time.Time:
 		if err = buffer.WriteByte('\''); err != nil {
@@ -101,6 +136,8 @@ func explainArgument(buffer *bytes.Buffer, arg any) (err error) {
 				return err
 			}
 		}
+	case arrayArgument:
+		return explainArgument(buffer, converter, raw.value)
 	case driver.Valuer:
 		var value = reflect.ValueOf(raw)
 		if !value.IsValid() {
@@ -112,8 +149,10 @@ func explainArgument(buffer *bytes.Buffer, arg any) (err error) {
 			if err != nil {
 				return err
 			}
-			return explainArgument(buffer, rawValue)
+			return explainArgument(buffer, converter, rawValue)
 		}
+	case []byte:
+		return explainArgument(buffer, converter, string(raw))
 	case bool:
 		if _, err = buffer.WriteString(strconv.FormatBool(raw)); err != nil {
 			return err
@@ -177,6 +216,14 @@ func explainArgument(buffer *bytes.Buffer, arg any) (err error) {
 			return err
 		}
 	default:
+		var nValue, converted, err = encodeArgument(converter, raw)
+		if err != nil {
+			return err
+		}
+		if converted {
+			return explainArgument(buffer, converter, nValue)
+		}
+
 		var value = reflect.ValueOf(raw)
 		var kind = value.Kind()
 
@@ -195,24 +242,42 @@ func explainArgument(buffer *bytes.Buffer, arg any) (err error) {
 				}
 				return nil
 			}
-			return explainArgument(buffer, reflect.Indirect(value).Interface())
+			return explainArgument(buffer, converter, reflect.Indirect(value).Interface())
 		case reflect.Bool:
-			return explainArgument(buffer, value.Bool())
+			return explainArgument(buffer, converter, value.Bool())
 		case reflect.String:
-			return explainArgument(buffer, value.String())
+			return explainArgument(buffer, converter, value.String())
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return explainArgument(buffer, value.Int())
+			return explainArgument(buffer, converter, value.Int())
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			return explainArgument(buffer, value.Uint())
+			return explainArgument(buffer, converter, value.Uint())
 		case reflect.Float32, reflect.Float64:
-			return explainArgument(buffer, value.Float())
+			return explainArgument(buffer, converter, value.Float())
+		case reflect.Slice, reflect.Array:
+			// 使用 SliceArray 策略时切片会作为一个数组参数绑定
+			if _, err = buffer.WriteString("ARRAY["); err != nil {
+				return err
+			}
+			for idx := 0; idx < value.Len(); idx++ {
+				if idx != 0 {
+					if err = buffer.WriteByte(','); err != nil {
+						return err
+					}
+				}
+				if err = explainArgument(buffer, converter, value.Index(idx).Interface()); err != nil {
+					return err
+				}
+			}
+			if err = buffer.WriteByte(']'); err != nil {
+				return err
+			}
 		default:
 			for _, rType := range convertibleTypes {
 				if value.Type().ConvertibleTo(rType) {
-					return explainArgument(buffer, value.Convert(rType).Interface())
+					return explainArgument(buffer, converter, value.Convert(rType).Interface())
 				}
 			}
-			return explainArgument(buffer, value.String())
+			return explainArgument(buffer, converter, value.String())
 		}
 	}
 	return nil
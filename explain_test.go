@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
 )
 
 func TestExplain(t *testing.T) {
@@ -29,6 +30,26 @@ func TestExplain(t *testing.T) {
 			Clause:    dbs.SQL("id IN (?)", []AliasInt{1, 2, 3, 4, 5}),
 			ExpectSQL: "id IN (1,2,3,4,5)",
 		},
+		{
+			Clause:    dbs.NewSelectBuilder().UseSliceStrategy(dbs.SliceArray).Selects("id").Table("user").Where("id IN (?)", []int{1, 2, 3}),
+			ExpectSQL: "SELECT id FROM user WHERE id = ANY(ARRAY[1,2,3])",
+		},
+		{
+			Clause:    dbs.NewSelectBuilder().UseDialect(postgres.Dialect()).Selects("id").Table("user").Where("id = ? AND name = ?", 10, "a"),
+			ExpectSQL: "SELECT id FROM user WHERE id = 10 AND name = 'a'",
+		},
+		{
+			Clause:    dbs.SQL("data = ?", []byte("abc")),
+			ExpectSQL: "data = 'abc'",
+		},
+		{
+			Clause:    dbs.SQL("tags && ?", dbs.Array([]string{"a", "b"})),
+			ExpectSQL: `tags && '{"a","b"}'`,
+		},
+		{
+			Clause:    dbs.SQL("id = ANY(?)", dbs.Array([]int64{1, 2})),
+			ExpectSQL: "id = ANY('{1,2}')",
+		},
 		{
 			Clause:    dbs.SQL("id = (?)", dbs.SQL("SELECT id FROM user where phone = ?", "12345678901")),
 			ExpectSQL: "id = (SELECT id FROM user where phone = '12345678901')",
@@ -45,3 +66,37 @@ func TestExplain(t *testing.T) {
 		}
 	}
 }
+
+func TestExplainSQL_Placeholder(t *testing.T) {
+	var tests = []struct {
+		SQL       string
+		Args      []any
+		ExpectSQL string
+	}{
+		{
+			SQL:       "a = $2 OR b = $1 OR c = $2",
+			Args:      []any{1, "x"},
+			ExpectSQL: "a = 'x' OR b = 1 OR c = 'x'",
+		},
+		{
+			SQL:       "a = $1 OR b = $3 OR c = $$x$$",
+			Args:      []any{1},
+			ExpectSQL: "a = 1 OR b = $3 OR c = $$x$$",
+		},
+		{
+			SQL:       "a = ? OR b = ?",
+			Args:      []any{1},
+			ExpectSQL: "a = 1 OR b = ?",
+		},
+	}
+
+	for _, test := range tests {
+		var sql, err = dbs.ExplainSQL(test.SQL, test.Args)
+		if err != nil {
+			t.Fatal("生成 SQL 语句发生错误:", err)
+		}
+		if sql != test.ExpectSQL {
+			t.Fatalf("期望 SQL: %s, 实际 SQL: %s", test.ExpectSQL, sql)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package dbs
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ColumnScanner 是实体可以实现的可选接口，Mapper 将查询结果映射到该实体时不再使用反射。
+//
+// ScanColumns 返回与 columns 一一对应的扫描目标（通常为字段的指针），不需要的列返回 nil。
+// 实现了该接口的实体不进行严格模式检查，需要在 ScanColumns 中自行处理 NULL（如使用指针或者 sql.NullString 等类型的字段，或者使用 Null() 包装扫描目标）。
+type ColumnScanner interface {
+	ScanColumns(columns []string) []any
+}
+
+// FieldEncoder 是实体可以实现的可选接口，Mapper 编码该实体时直接使用 EncodeFields 的返回值，不再使用反射。
+type FieldEncoder interface {
+	EncodeFields() []FieldValue
+}
+
+var columnScannerType = reflect.TypeOf((*ColumnScanner)(nil)).Elem()
+
+func isColumnScanner(destType reflect.Type) bool {
+	return reflect.PointerTo(destType).Implements(columnScannerType)
+}
+
+func columnNames(columns []*sql.ColumnType) []string {
+	var names = make([]string, len(columns))
+	for idx, column := range columns {
+		names[idx] = column.Name()
+	}
+	return names
+}
+
+func scanColumns(rows *sql.Rows, names []string, dest ColumnScanner) error {
+	var targets = dest.ScanColumns(names)
+	if len(targets) != len(names) {
+		return fmt.Errorf("dbs: %T.ScanColumns returned %d targets for %d columns", dest, len(targets), len(names))
+	}
+	for idx, target := range targets {
+		if target == nil {
+			var discard any
+			targets[idx] = &discard
+		}
+	}
+	return rows.Scan(targets...)
+}
+
+func scanIntoColumnScanners(rows *sql.Rows, columns []*sql.ColumnType, destType reflect.Type, isPointer bool, destValue reflect.Value) error {
+	var names = columnNames(columns)
+	var nList = make([]reflect.Value, 0, 20)
+	for {
+		var nPointer = reflect.New(destType)
+		if err := scanColumns(rows, names, nPointer.Interface().(ColumnScanner)); err != nil {
+			return err
+		}
+
+		if isPointer {
+			nList = append(nList, nPointer)
+		} else {
+			nList = append(nList, nPointer.Elem())
+		}
+
+		if !rows.Next() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(nList) > 0 {
+		destValue.Set(reflect.Append(destValue, nList...))
+	}
+	return nil
+}
+
+// Null 返回扫描到 dest 的 sql.Scanner，扫描到 NULL 时将 dest 设置为零值，与 Mapper 将 NULL 映射到非指针字段的行为一致，
+// 可以在 ScanColumns 中使用（dbsgen 生成的代码也使用该函数），如：
+//
+//	targets[idx] = dbs.Null(&u.Name)
+//
+// 其它值按照 database/sql 的规则进行转换，*T 实现了 sql.Scanner 时交由其 Scan 方法处理。
+func Null[T any](dest *T) sql.Scanner {
+	return nullScanner[T]{dest: dest}
+}
+
+type nullScanner[T any] struct {
+	dest *T
+}
+
+func (s nullScanner[T]) Scan(src any) error {
+	if src == nil {
+		var zero T
+		*s.dest = zero
+		return nil
+	}
+
+	switch dest := any(s.dest).(type) {
+	case sql.Scanner:
+		return dest.Scan(src)
+	case *string:
+		var value sql.NullString
+		var err = value.Scan(src)
+		*dest = value.String
+		return err
+	case *int64:
+		var value sql.NullInt64
+		var err = value.Scan(src)
+		*dest = value.Int64
+		return err
+	case *float64:
+		var value sql.NullFloat64
+		var err = value.Scan(src)
+		*dest = value.Float64
+		return err
+	case *bool:
+		var value sql.NullBool
+		var err = value.Scan(src)
+		*dest = value.Bool
+		return err
+	case *time.Time:
+		var value sql.NullTime
+		var err = value.Scan(src)
+		*dest = value.Time
+		return err
+	case *[]byte:
+		switch raw := src.(type) {
+		case []byte:
+			*dest = append([]byte(nil), raw...)
+		case string:
+			*dest = []byte(raw)
+		default:
+			var value sql.NullString
+			if err := value.Scan(src); err != nil {
+				return err
+			}
+			*dest = []byte(value.String)
+		}
+		return nil
+	}
+	return scanNullValue(reflect.ValueOf(s.dest).Elem(), src)
+}
+
+// scanNullValue 处理其它类型（如 int、uint32 或者基础类型为 int 的自定义类型），先借助 sql.NullXxx 完成转换，再设置到 dest。
+func scanNullValue(dest reflect.Value, src any) error {
+	switch kind := dest.Kind(); {
+	case kind == reflect.String:
+		var value sql.NullString
+		if err := value.Scan(src); err != nil {
+			return err
+		}
+		dest.SetString(value.String)
+		return nil
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		var value sql.NullString
+		if err := value.Scan(src); err != nil {
+			return err
+		}
+		var n, err = strconv.ParseInt(value.String, 10, dest.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("dbs: converting driver.Value type %T (%q) to a %s: %w", src, value.String, dest.Type(), err)
+		}
+		dest.SetInt(n)
+		return nil
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		var value sql.NullString
+		if err := value.Scan(src); err != nil {
+			return err
+		}
+		var n, err = strconv.ParseUint(value.String, 10, dest.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("dbs: converting driver.Value type %T (%q) to a %s: %w", src, value.String, dest.Type(), err)
+		}
+		dest.SetUint(n)
+		return nil
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		var value sql.NullFloat64
+		if err := value.Scan(src); err != nil {
+			return err
+		}
+		dest.SetFloat(value.Float64)
+		return nil
+	case kind == reflect.Bool:
+		var value sql.NullBool
+		if err := value.Scan(src); err != nil {
+			return err
+		}
+		dest.SetBool(value.Bool)
+		return nil
+	}
+
+	if value, ok := convertValue(reflect.ValueOf(src), dest.Type()); ok {
+		dest.Set(value)
+		return nil
+	}
+	return fmt.Errorf("dbs: unsupported Scan, storing driver.Value type %T into type %s", src, dest.Type())
+}
@@ -0,0 +1,199 @@
+package dbs_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/internal/dbstest"
+)
+
+func openMemoryDB(t testing.TB, rows int) *dbs.DB {
+	var result = dbstest.Result{Columns: []string{"id", "name", "email", "age", "unknown"}}
+	for idx := 0; idx < rows; idx++ {
+		result.Rows = append(result.Rows, []driver.Value{int64(idx), "name" + strconv.Itoa(idx), []byte("mail" + strconv.Itoa(idx)), int64(idx % 100), nil})
+	}
+	return dbstest.Open(t, dbstest.NewDriver(map[string]dbstest.Result{"": result}))
+}
+
+type reflectUser struct {
+	Id    int64  `sql:"id"`
+	Name  string `sql:"name"`
+	Email string `sql:"email"`
+	Age   int    `sql:"age"`
+}
+
+type fastUser struct {
+	Id    int64  `sql:"id"`
+	Name  string `sql:"name"`
+	Email string `sql:"email"`
+	Age   int    `sql:"age"`
+}
+
+func (u *fastUser) ScanColumns(columns []string) []any {
+	var targets = make([]any, len(columns))
+	for idx, column := range columns {
+		switch column {
+		case "id":
+			targets[idx] = &u.Id
+		case "name":
+			targets[idx] = &u.Name
+		case "email":
+			targets[idx] = &u.Email
+		case "age":
+			targets[idx] = &u.Age
+		}
+	}
+	return targets
+}
+
+func (u fastUser) EncodeFields() []dbs.FieldValue {
+	return []dbs.FieldValue{
+		{Name: "id", Value: u.Id},
+		{Name: "name", Value: u.Name},
+		{Name: "email", Value: u.Email},
+		{Name: "age", Value: u.Age},
+	}
+}
+
+func TestMapper_ColumnScanner(t *testing.T) {
+	var db = openMemoryDB(t, 3)
+
+	var reflectUsers, err = dbs.Query[[]*reflectUser](context.Background(), db, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fastUsers, err := dbs.Query[[]*fastUser](context.Background(), db, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fastUsers) != len(reflectUsers) {
+		t.Fatalf("期望行数: %d, 实际行数: %d", len(reflectUsers), len(fastUsers))
+	}
+	for idx := range fastUsers {
+		if reflectUser(*fastUsers[idx]) != *reflectUsers[idx] {
+			t.Fatalf("期望: %+v, 实际: %+v", reflectUsers[idx], fastUsers[idx])
+		}
+	}
+
+	user, err := dbs.Query[fastUser](context.Background(), db, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "mail0" {
+		t.Fatalf("期望: %s, 实际: %s", "mail0", user.Email)
+	}
+
+	cursor, err := dbs.Iterate[*fastUser](context.Background(), db, dbs.SQL("SELECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		if expect := "name" + strconv.Itoa(cursor.Total()-1); cursor.Value().Name != expect {
+			t.Fatalf("期望: %s, 实际: %s", expect, cursor.Value().Name)
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapper_FieldEncoder(t *testing.T) {
+	var mapper = dbs.NewMapper("sql")
+
+	var user = fastUser{Id: 1, Name: "a", Email: "b", Age: 2}
+	var fastValues, err = mapper.Encode(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reflectValues, err := mapper.Encode(reflectUser(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fastValues, reflectValues) {
+		t.Fatalf("期望: %+v, 实际: %+v", reflectValues, fastValues)
+	}
+
+	if _, err = mapper.Encode((*fastUser)(nil)); err != dbs.ErrInvalidEncodeValue {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrInvalidEncodeValue, err)
+	}
+}
+
+func benchmarkDecode[T any](b *testing.B) {
+	var db = openMemoryDB(b, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dbs.Query[[]*T](context.Background(), db, "SELECT"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapper_DecodeReflect(b *testing.B) {
+	benchmarkDecode[reflectUser](b)
+}
+
+func BenchmarkMapper_DecodeColumnScanner(b *testing.B) {
+	benchmarkDecode[fastUser](b)
+}
+
+func benchmarkEncode(b *testing.B, src any) {
+	var mapper = dbs.NewMapper("sql")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := mapper.Encode(src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapper_EncodeReflect(b *testing.B) {
+	benchmarkEncode(b, &reflectUser{Id: 1, Name: "a", Email: "b", Age: 2})
+}
+
+func BenchmarkMapper_EncodeFieldEncoder(b *testing.B) {
+	benchmarkEncode(b, &fastUser{Id: 1, Name: "a", Email: "b", Age: 2})
+}
+
+type nullStatus int
+
+func TestNull(t *testing.T) {
+	var name = "name"
+	if err := dbs.Null(&name).Scan(nil); err != nil || name != "" {
+		t.Fatalf("期望: 空字符串, 实际: %q, 错误: %v", name, err)
+	}
+	if err := dbs.Null(&name).Scan([]byte("mail")); err != nil || name != "mail" {
+		t.Fatalf("期望: %q, 实际: %q, 错误: %v", "mail", name, err)
+	}
+
+	var age int
+	if err := dbs.Null(&age).Scan("18"); err != nil || age != 18 {
+		t.Fatalf("期望: %d, 实际: %d, 错误: %v", 18, age, err)
+	}
+
+	var status nullStatus
+	if err := dbs.Null(&status).Scan(int64(2)); err != nil || status != 2 {
+		t.Fatalf("期望: %d, 实际: %d, 错误: %v", 2, status, err)
+	}
+
+	var small uint8
+	if err := dbs.Null(&small).Scan(int64(256)); err == nil {
+		t.Fatalf("期望溢出错误, 实际: %d", small)
+	}
+
+	// *T 实现了 sql.Scanner 时交由其处理非 NULL 的值
+	var value = sql.NullString{String: "a", Valid: true}
+	if err := dbs.Null(&value).Scan(nil); err != nil || value.Valid {
+		t.Fatalf("期望: %+v, 实际: %+v, 错误: %v", sql.NullString{}, value, err)
+	}
+	if err := dbs.Null(&value).Scan("b"); err != nil || value.String != "b" || !value.Valid {
+		t.Fatalf("期望: %q, 实际: %+v, 错误: %v", "b", value, err)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 )
 
 type InsertBuilder struct {
 	dialect  Dialect
 	session  Session
+	strategy SliceStrategy
 	prefixes *Clauses
 	options  *Clauses
 	columns  Parts
@@ -24,9 +26,32 @@ func NewInsertBuilder() *InsertBuilder {
 	return ib
 }
 
+func (ib *InsertBuilder) Clone() *InsertBuilder {
+	if ib == nil {
+		return nil
+	}
+	var nib = &InsertBuilder{}
+	nib.dialect = ib.dialect
+	nib.session = ib.session
+	nib.strategy = ib.strategy
+	nib.prefixes = ib.prefixes.Clone()
+	nib.options = ib.options.Clone()
+	nib.columns = ib.columns.Clone()
+	nib.table = ib.table
+	if len(ib.values) > 0 {
+		nib.values = make([][]any, len(ib.values))
+		for i, values := range ib.values {
+			nib.values[i] = cloneValues(values)
+		}
+	}
+	nib.suffixes = ib.suffixes.Clone()
+	return nib
+}
+
 func (ib *InsertBuilder) Reset() {
 	ib.dialect = nil
 	ib.session = nil
+	ib.strategy = SliceDefault
 	ib.prefixes.reset()
 	ib.options.reset()
 	ib.columns = ib.columns[:0]
@@ -48,6 +73,11 @@ func (ib *InsertBuilder) UseSession(session Session) *InsertBuilder {
 	return ib
 }
 
+func (ib *InsertBuilder) UseSliceStrategy(strategy SliceStrategy) *InsertBuilder {
+	ib.strategy = strategy
+	return ib
+}
+
 func (ib *InsertBuilder) Prefix(sql any, args ...any) *InsertBuilder {
 	if ib.prefixes == nil {
 		ib.prefixes = NewClauses(' ')
@@ -89,7 +119,7 @@ func (ib *InsertBuilder) Suffix(sql any, args ...any) *InsertBuilder {
 	return ib
 }
 
-func (ib *InsertBuilder) Write(w Writer) (err error) {
+func (ib *InsertBuilder) Validate() error {
 	if len(ib.table) == 0 {
 		return errors.New("dbs: insert clause must specify a table")
 	}
@@ -100,6 +130,20 @@ func (ib *InsertBuilder) Write(w Writer) (err error) {
 		return errors.New("dbs: insert clause must specify values")
 	}
 
+	var columns = countColumns(ib.columns)
+	for row, values := range ib.values {
+		if len(values) != columns {
+			return &ValidationError{Row: row, Err: ErrInsertValuesCountMismatch}
+		}
+	}
+	return nil
+}
+
+func (ib *InsertBuilder) Write(w Writer) (err error) {
+	if err = ib.Validate(); err != nil {
+		return err
+	}
+
 	if ib.prefixes.valid() {
 		if err = ib.prefixes.Write(w); err != nil {
 			return err
@@ -154,30 +198,7 @@ func (ib *InsertBuilder) Write(w Writer) (err error) {
 					return err
 				}
 			}
-
-			if err = w.WriteByte('('); err != nil {
-				return err
-			}
-			for col, value := range values {
-				if col != 0 {
-					if err = w.WriteByte(','); err != nil {
-						return err
-					}
-				}
-
-				switch raw := value.(type) {
-				case SQLClause:
-					if err = raw.Write(w); err != nil {
-						return err
-					}
-				default:
-					if err = w.WriteArgument(FlagPlaceholder|FlagArgument, value); err != nil {
-						return err
-					}
-				}
-
-			}
-			if err = w.WriteByte(')'); err != nil {
+			if err = writeValues(w, values); err != nil {
 				return err
 			}
 		}
@@ -195,11 +216,45 @@ func (ib *InsertBuilder) Write(w Writer) (err error) {
 	return nil
 }
 
+func writeValues(w Writer, values []any) (err error) {
+	if err = w.WriteByte('('); err != nil {
+		return err
+	}
+	for col, value := range values {
+		if col != 0 {
+			if err = w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		switch raw := value.(type) {
+		case SQLClause:
+			if err = raw.Write(w); err != nil {
+				return err
+			}
+		default:
+			var nValue, _, err = convertArgument(w, value)
+			if err != nil {
+				return err
+			}
+			if err = w.WriteArgument(FlagPlaceholder|FlagArgument, nValue); err != nil {
+				return err
+			}
+		}
+	}
+	if err = w.WriteByte(')'); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (ib *InsertBuilder) SQL() (string, []any, error) {
 	var buffer = NewBuffer()
 	defer buffer.Release()
 
 	buffer.UseDialect(ib.dialect)
+	buffer.UseSliceStrategy(sliceStrategy(ib.strategy, ib.session))
+	buffer.UseConverter(converterFromSession(ib.session))
 
 	if err := ib.Write(buffer); err != nil {
 		return "", nil, err
@@ -207,6 +262,10 @@ func (ib *InsertBuilder) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+func (ib *InsertBuilder) chunk(offset int) (string, []any, int, error) {
+	return chunkSQL(ib, ib.session, ib.dialect, sliceStrategy(ib.strategy, ib.session), offset)
+}
+
 func (ib *InsertBuilder) Scan(ctx context.Context, dest any) error {
 	return scan(ctx, ib.session, ib, dest)
 }
@@ -218,3 +277,176 @@ func (ib *InsertBuilder) ScanRow(ctx context.Context, dest ...any) error {
 func (ib *InsertBuilder) Exec(ctx context.Context) (sql.Result, error) {
 	return exec(ctx, ib.session, ib)
 }
+
+// Split 按照 Dialect 声明的参数数量和字节数限制（参考 Limiter）将多行 VALUES 拆分为多个 InsertBuilder。
+//
+// Dialect 没有声明限制时返回只包含当前 InsertBuilder 的切片。
+func (ib *InsertBuilder) Split() ([]*InsertBuilder, error) {
+	var maxParameters, maxBytes = dialectLimits(ib.dialect)
+	return ib.SplitBy(maxParameters, maxBytes)
+}
+
+// SplitBy 将多行 VALUES 拆分为多个 InsertBuilder，确保每条语句的参数数量不超过 maxParameters，
+// 预估字节数不超过 maxBytes，0 表示不限制。
+//
+// 单行数据超出限制时，该行会被单独拆分为一条语句。
+func (ib *InsertBuilder) SplitBy(maxParameters, maxBytes int) ([]*InsertBuilder, error) {
+	if err := ib.Validate(); err != nil {
+		return nil, err
+	}
+	if maxParameters <= 0 && maxBytes <= 0 {
+		return []*InsertBuilder{ib}, nil
+	}
+
+	var costs = make([]insertCost, len(ib.values))
+	var total insertCost
+	for row, values := range ib.values {
+		var cost, err = measureValues(values)
+		if err != nil {
+			return nil, err
+		}
+		costs[row] = cost
+		total.parameters += cost.parameters
+		total.bytes += cost.bytes + 1
+	}
+
+	// 语句中除 VALUES 以外的部分（表名、列名、前缀及后缀等）
+	var base, err = ib.measureBase(costs[0])
+	if err != nil {
+		return nil, err
+	}
+	if fits(base, total, maxParameters, maxBytes) {
+		return []*InsertBuilder{ib}, nil
+	}
+
+	var builders = make([]*InsertBuilder, 0, 2)
+	var start = 0
+	var current insertCost
+	for row, cost := range costs {
+		var next = insertCost{parameters: current.parameters + cost.parameters, bytes: current.bytes + cost.bytes + 1}
+		if row > start && !fits(base, next, maxParameters, maxBytes) {
+			builders = append(builders, ib.slice(start, row))
+			start = row
+			next = insertCost{parameters: cost.parameters, bytes: cost.bytes + 1}
+		}
+		current = next
+	}
+	builders = append(builders, ib.slice(start, len(costs)))
+	return builders, nil
+}
+
+// ExecInBatches 按照 Dialect 声明的限制拆分多行 VALUES 并依次执行，返回的 sql.Result 汇总了所有语句的执行结果。
+//
+// 拆分为多条语句且 Session 为 Database 时，所有语句会在同一个事务中执行；
+// Session 为 *Tx 等其它类型时，直接在该 Session 上执行，事务由调用方管理。
+func (ib *InsertBuilder) ExecInBatches(ctx context.Context) (result sql.Result, err error) {
+	var builders []*InsertBuilder
+	if builders, err = ib.Split(); err != nil {
+		return nil, err
+	}
+
+	var session = ib.session
+	var tx *Tx
+	if database, ok := session.(Database); ok && len(builders) > 1 {
+		if tx, err = database.BeginTx(ctx, nil); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+		session = tx
+	}
+
+	var results = make(insertResults, 0, len(builders))
+	for _, builder := range builders {
+		if result, err = exec(ctx, session, builder); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if tx != nil {
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (ib *InsertBuilder) slice(start, end int) *InsertBuilder {
+	var nib = *ib
+	nib.values = ib.values[start:end:end]
+	return &nib
+}
+
+func (ib *InsertBuilder) measureBase(first insertCost) (insertCost, error) {
+	var nib = ib.slice(0, 1)
+
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	if err := nib.Write(buffer); err != nil {
+		return insertCost{}, err
+	}
+	var args = buffer.Arguments()
+	return insertCost{
+		parameters: len(args) - first.parameters,
+		bytes:      buffer.Len() + argumentsSize(args) - first.bytes,
+	}, nil
+}
+
+type insertCost struct {
+	parameters int
+	bytes      int
+}
+
+func fits(base, cost insertCost, maxParameters, maxBytes int) bool {
+	if maxParameters > 0 && base.parameters+cost.parameters > maxParameters {
+		return false
+	}
+	if maxBytes > 0 && base.bytes+cost.bytes > maxBytes {
+		return false
+	}
+	return true
+}
+
+func measureValues(values []any) (insertCost, error) {
+	var buffer = NewBuffer()
+	defer buffer.Release()
+
+	if err := writeValues(buffer, values); err != nil {
+		return insertCost{}, err
+	}
+	var args = buffer.Arguments()
+	return insertCost{parameters: len(args), bytes: buffer.Len() + argumentsSize(args)}, nil
+}
+
+// argumentsSize 预估参数在网络传输中占用的字节数。
+func argumentsSize(args []any) int {
+	var size int
+	for _, arg := range args {
+		switch raw := arg.(type) {
+		case string:
+			size += len(raw)
+		case []byte:
+			size += len(raw)
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// countColumns 统计列的数量，Columns("name,age") 与 Columns("name", "age") 是等价的。
+func countColumns(columns Parts) int {
+	var count int
+	for _, column := range columns {
+		if len(column) == 0 {
+			continue
+		}
+		count += strings.Count(column, ",") + 1
+	}
+	return count
+}
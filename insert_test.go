@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
 )
 
 func TestInsertBuilder(t *testing.T) {
@@ -17,6 +18,59 @@ func TestInsertBuilder(t *testing.T) {
 	t.Log(ib.SQL())
 }
 
+func TestInsertBuilder_Clone(t *testing.T) {
+	var ib = dbs.NewInsertBuilder()
+	ib.Table("user")
+	ib.Columns("id", "name")
+	ib.Values(1, dbs.SQL("UPPER(?)", "a"))
+
+	var nib = ib.Clone()
+	nib.Values(2, "b")
+	nib.Suffix("ON DUPLICATE KEY UPDATE name = VALUES(name)")
+
+	checkClause(t, ib, "INSERT INTO user (id,name) VALUES (?,UPPER(?))", ExpectArgs(1, "a"))
+	checkClause(t, nib, "INSERT INTO user (id,name) VALUES (?,UPPER(?)),(?,?) ON DUPLICATE KEY UPDATE name = VALUES(name)", ExpectArgs(1, "a", 2, "b"))
+
+	var rb = dbs.NewInsertBuilder()
+	rb.Reset()
+	rb.Clone().Reset()
+}
+
+func TestInsertBuilder_SplitBy(t *testing.T) {
+	var ib = dbs.NewInsertBuilder()
+	ib.Table("user")
+	ib.Columns("id", "name")
+	for i := 1; i <= 5; i++ {
+		ib.Values(i, "Sample")
+	}
+	ib.Suffix("ON CONFLICT (id) DO UPDATE SET updated_at = ?", 100)
+
+	var builders, err = ib.SplitBy(5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builders) != 3 {
+		t.Fatalf("期望拆分数量: %d, 实际拆分数量: %d", 3, len(builders))
+	}
+	checkClause(t, builders[0], "INSERT INTO user (id,name) VALUES (?,?),(?,?) ON CONFLICT (id) DO UPDATE SET updated_at = ?", ExpectArgs(1, "Sample", 2, "Sample", 100))
+	checkClause(t, builders[1], "INSERT INTO user (id,name) VALUES (?,?),(?,?) ON CONFLICT (id) DO UPDATE SET updated_at = ?", ExpectArgs(3, "Sample", 4, "Sample", 100))
+	checkClause(t, builders[2], "INSERT INTO user (id,name) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET updated_at = ?", ExpectArgs(5, "Sample", 100))
+
+	if builders, err = ib.SplitBy(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(builders) != 1 {
+		t.Fatalf("期望拆分数量: %d, 实际拆分数量: %d", 1, len(builders))
+	}
+
+	if builders, err = ib.UseDialect(postgres.Dialect()).Split(); err != nil {
+		t.Fatal(err)
+	}
+	if len(builders) != 1 {
+		t.Fatalf("期望拆分数量: %d, 实际拆分数量: %d", 1, len(builders))
+	}
+}
+
 func BenchmarkInsertBuilder(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		var ib = dbs.NewInsertBuilder()
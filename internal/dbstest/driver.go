@@ -0,0 +1,187 @@
+// Package dbstest 提供用于测试的 database/sql 驱动，用于在没有数据库的情况下测试 dbs 以及其它子包。
+package dbstest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+)
+
+// Result 为查询返回的数据。
+type Result struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// Driver 记录事务以及执行的语句，并按照查询语句中包含的关键字返回预先设置的数据。
+//
+// 多个关键字都匹配时使用最长的关键字，关键字为空字符串时匹配所有查询，没有匹配的关键字时返回空的结果。
+type Driver struct {
+	mu      sync.Mutex
+	results map[string]Result
+	logs    []string
+
+	// Fail 在执行语句（包括查询）之前调用，返回非 nil 时语句执行失败，也可以用于维护测试需要的状态。
+	Fail func(query string, args []driver.Value) error
+}
+
+func NewDriver(results map[string]Result) *Driver {
+	var d = &Driver{results: make(map[string]Result, len(results))}
+	for keyword, result := range results {
+		d.results[keyword] = result
+	}
+	return d
+}
+
+// Open 使用 d 创建 dbs.DB，测试结束之后关闭，不输出日志。
+func Open(t testing.TB, d *Driver) *dbs.DB {
+	var rawDB = sql.OpenDB(d)
+	t.Cleanup(func() {
+		rawDB.Close()
+	})
+	var db = dbs.New(rawDB)
+	db.UseLogger(nil)
+	return db
+}
+
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	return &conn{driver: d}, nil
+}
+
+func (d *Driver) Connect(ctx context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *Driver) Driver() driver.Driver {
+	return d
+}
+
+// SetResult 设置查询语句包含 keyword 时返回的数据。
+func (d *Driver) SetResult(keyword string, result Result) {
+	d.mu.Lock()
+	d.results[keyword] = result
+	d.mu.Unlock()
+}
+
+// Statements 返回按照顺序执行的语句，包括 BEGIN、COMMIT 以及 ROLLBACK。
+func (d *Driver) Statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.logs...)
+}
+
+// Reset 清除已经记录的语句。
+func (d *Driver) Reset() {
+	d.mu.Lock()
+	d.logs = nil
+	d.mu.Unlock()
+}
+
+func (d *Driver) log(query string) {
+	d.mu.Lock()
+	d.logs = append(d.logs, query)
+	d.mu.Unlock()
+}
+
+func (d *Driver) result(query string) Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var matched = -1
+	var nResult Result
+	for keyword, result := range d.results {
+		if len(keyword) > matched && strings.Contains(query, keyword) {
+			matched = len(keyword)
+			nResult = result
+		}
+	}
+	return nResult
+}
+
+type conn struct {
+	driver *Driver
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{driver: c.driver, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.driver.log("BEGIN")
+	return c, nil
+}
+
+func (c *conn) Commit() error {
+	c.driver.log("COMMIT")
+	return nil
+}
+
+func (c *conn) Rollback() error {
+	c.driver.log("ROLLBACK")
+	return nil
+}
+
+type stmt struct {
+	driver *Driver
+	query  string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.driver.Fail != nil {
+		if err := s.driver.Fail(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	s.driver.log(s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.driver.Fail != nil {
+		if err := s.driver.Fail(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	s.driver.log(s.query)
+	return &rows{result: s.driver.result(s.query)}, nil
+}
+
+type rows struct {
+	result Result
+	offset int
+}
+
+func (r *rows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.offset >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.offset])
+	r.offset++
+	return nil
+}
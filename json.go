@@ -0,0 +1,73 @@
+package dbs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONCodec 用于编码和解码设置了 json 标签选项的字段，默认使用 encoding/json。
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+
+	Unmarshal(data []byte, v any) error
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithJSONCodec 设置 json 标签选项使用的编解码器，如 jsoniter、sonic 等。
+func WithJSONCodec(codec JSONCodec) MapperOption {
+	return func(m *mapper) {
+		if codec != nil {
+			m.json = codec
+		}
+	}
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// jsonCodec 用于设置了 json 标签选项的字段。
+type jsonCodec struct {
+	codec JSONCodec
+}
+
+func (c jsonCodec) scanType() reflect.Type {
+	return bytesType
+}
+
+// encode 将字段编码为 JSON 字符串，值为 nil 的指针、map、切片以及接口编码为 NULL。
+func (c jsonCodec) encode(column string, value reflect.Value) (any, error) {
+	if isNilValue(value) {
+		return nil, nil
+	}
+	var data, err = c.codec.Marshal(value.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("dbs: encode json column %q: %w", column, err)
+	}
+	return string(data), nil
+}
+
+func (c jsonCodec) decode(column string, src any, dest reflect.Value) error {
+	var nPointer = reflect.New(dest.Type())
+	if err := c.codec.Unmarshal(src.([]byte), nPointer.Interface()); err != nil {
+		return fmt.Errorf("dbs: decode json column %q: %w", column, err)
+	}
+	dest.Set(nPointer.Elem())
+	return nil
+}
+
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package dbs_test
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+)
+
+// callerLogger 记录每次 Trace() 时 depth 对应的调用位置。
+type callerLogger struct {
+	callers []string
+}
+
+func (l *callerLogger) Trace(ctx context.Context, depth int, begin time.Time, sql string, args []any, rowsAffected int64, err error) {
+	var _, file, line, _ = runtime.Caller(depth)
+	l.callers = append(l.callers, filepath.Base(file)+":"+strconv.Itoa(line))
+}
+
+// caller 返回调用 caller 的下一行的位置。
+func caller() string {
+	var _, file, line, _ = runtime.Caller(1)
+	return filepath.Base(file) + ":" + strconv.Itoa(line+1)
+}
+
+func TestLogger_Caller(t *testing.T) {
+	var db, _ = openTxDB(t)
+	var logger = &callerLogger{}
+	db.UseLogger(logger)
+	var ctx = context.Background()
+
+	var ib = dbs.NewInsertBuilder()
+	ib.UseSession(db)
+	ib.Table("user")
+	ib.Columns("id")
+	ib.Values(1)
+
+	var expect []string
+	expect = append(expect, caller())
+	if _, err := ib.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expect = append(expect, caller())
+	if _, err := ib.ExecInBatches(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logger.callers) != len(expect) {
+		t.Fatalf("期望记录: %v, 实际记录: %v", expect, logger.callers)
+	}
+	for idx := range expect {
+		if logger.callers[idx] != expect[idx] {
+			t.Fatalf("期望位置: %s, 实际位置: %s", expect[idx], logger.callers[idx])
+		}
+	}
+}
+
+func TestLogger_CursorCaller(t *testing.T) {
+	var db = openMemoryDB(t, 2)
+	var logger = &callerLogger{}
+	db.UseLogger(logger)
+	var ctx = context.Background()
+
+	// 读取完所有数据时记录最后一次调用 Next() 的位置
+	var cursor, err = dbs.Iterate[*fastUser](ctx, db, dbs.SQL("SELECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var next string
+	for {
+		next = caller()
+		if !cursor.Next() {
+			break
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+	var expect = []string{next}
+
+	if cursor, err = dbs.Iterate[*fastUser](ctx, db, dbs.SQL("SELECT")); err != nil {
+		t.Fatal(err)
+	}
+	expect = append(expect, caller())
+	cursor.Close()
+
+	expect = append(expect, caller())
+	if _, err = dbs.Iterate[*fastUser](ctx, db, dbs.SQL("SELECT ?")); err == nil {
+		t.Fatal("期望参数数量错误")
+	}
+
+	if len(logger.callers) != len(expect) {
+		t.Fatalf("期望记录: %v, 实际记录: %v", expect, logger.callers)
+	}
+	for idx := range expect {
+		if logger.callers[idx] != expect[idx] {
+			t.Fatalf("期望位置: %s, 实际位置: %s", expect[idx], logger.callers[idx])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DuplicatePolicy 用于指定将查询结果映射到 map[K]T 或者 map[K]*T 时，多行数据拥有相同键的处理方式。
+type DuplicatePolicy uint8
+
+const (
+	// DuplicateError 返回 *DuplicateKeyError，默认使用该策略。
+	DuplicateError DuplicatePolicy = iota
+
+	// DuplicateKeepFirst 保留第一次出现的数据行。
+	DuplicateKeepFirst
+
+	// DuplicateKeepLast 保留最后一次出现的数据行。
+	DuplicateKeepLast
+)
+
+var ErrDuplicateKey = errors.New("dbs: duplicate map key")
+var ErrMapKeyColumn = errors.New("dbs: map key column not found")
+
+type mapKeyKey struct{}
+
+type mapKeyOption struct {
+	column string
+	policy DuplicatePolicy
+}
+
+// ContextWithMapKey 为单次查询指定映射到 map[K]T、map[K]*T 或者 map[K][]T 时作为键的列，以及出现重复键时的处理方式（map[K][]T 会按键分组，不受 policy 影响）。
+//
+// column 为空时使用结构体中使用 pk 标签标记的字段，没有 pk 标签时使用 Entity.PrimaryKey() 返回的列。
+func ContextWithMapKey(ctx context.Context, column string, policy DuplicatePolicy) context.Context {
+	return context.WithValue(ctx, mapKeyKey{}, mapKeyOption{column: column, policy: policy})
+}
+
+func mapKeyFromContext(ctx context.Context) mapKeyOption {
+	if value, ok := ctx.Value(mapKeyKey{}).(mapKeyOption); ok {
+		return value
+	}
+	return mapKeyOption{}
+}
+
+// DuplicateKeyError 描述出现重复键的数据行，可以通过 errors.As 获取，errors.Is(err, ErrDuplicateKey) 返回 true。
+type DuplicateKeyError struct {
+	Column string
+	Key    any
+	Row    int // 从 0 开始
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("%v: %s = %v (row: %d)", ErrDuplicateKey, e.Column, e.Key, e.Row)
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// isKeyedMap 判断 map 的值是否为结构体、结构体指针或者它们的切片，这类 map 会以某一列作为键映射多行数据，
+// 其它 map（如 map[string]any）仍然表示单行数据。
+func isKeyedMap(destType reflect.Type) bool {
+	if destType.Kind() != reflect.Map {
+		return false
+	}
+	var elemType = destType.Elem()
+	if elemType.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	return elemType.Kind() == reflect.Struct && !isValueType(elemType)
+}
+
+func (m *mapper) scanKeyedMap(ctx context.Context, rows *sql.Rows, columns []*sql.ColumnType, strict bool, destType reflect.Type, destValue reflect.Value) (int, error) {
+	var elemType = destType.Elem()
+	var isGroup = elemType.Kind() == reflect.Slice
+
+	var structType = elemType
+	if isGroup {
+		structType = structType.Elem()
+	}
+	var isPointer = structType.Kind() == reflect.Ptr
+	if isPointer {
+		structType = structType.Elem()
+	}
+
+	var fields, values, err = m.prepare(structType, columns, strict)
+	defer func() {
+		for idx, value := range values {
+			var field = fields[idx]
+			if field != nil {
+				fields[idx].ValuePool.Put(value)
+			}
+		}
+	}()
+	if err != nil {
+		return 0, err
+	}
+
+	var option = mapKeyFromContext(ctx)
+	keyIndex, err := m.keyIndex(structType, columns, option.column)
+	if err != nil {
+		return 0, err
+	}
+	var keyColumn = columns[keyIndex].Name()
+
+	if destValue.IsNil() {
+		destValue.Set(reflect.MakeMap(destType))
+	}
+
+	var row = 0
+	for ; ; row++ {
+		var nPointer = reflect.New(structType)
+		var nValue = reflect.Indirect(nPointer)
+
+		if err = scanIntoStruct(rows, columns, fields, values, strict, row, nValue); err != nil {
+			return 0, err
+		}
+
+		key, err := mapKey(values[keyIndex], destType.Key(), keyColumn)
+		if err != nil {
+			return 0, err
+		}
+
+		var item = nValue
+		if isPointer {
+			item = nPointer
+		}
+
+		if isGroup {
+			var list = destValue.MapIndex(key)
+			if !list.IsValid() {
+				list = reflect.MakeSlice(elemType, 0, 1)
+			}
+			destValue.SetMapIndex(key, reflect.Append(list, item))
+		} else if destValue.MapIndex(key).IsValid() {
+			switch option.policy {
+			case DuplicateKeepFirst:
+			case DuplicateKeepLast:
+				destValue.SetMapIndex(key, item)
+			default:
+				return 0, &DuplicateKeyError{Column: keyColumn, Key: key.Interface(), Row: row}
+			}
+		} else {
+			destValue.SetMapIndex(key, item)
+		}
+
+		if !rows.Next() {
+			break
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	return row + 1, nil
+}
+
+// keyIndex 返回作为键的列在查询结果中的位置。
+func (m *mapper) keyIndex(structType reflect.Type, columns []*sql.ColumnType, column string) (int, error) {
+	if column == "" {
+		var mStruct, ok = m.getStructMetadata(structType)
+		if !ok {
+			mStruct = m.buildStructMetadata(structType)
+		}
+
+		var keys []string
+		for _, name := range mStruct.columns {
+			if field := mStruct.fields[name]; field != nil && field.PrimaryKey {
+				keys = append(keys, name)
+			}
+		}
+		switch {
+		case len(keys) == 1:
+			column = keys[0]
+		case len(keys) > 1:
+			return -1, fmt.Errorf("%w: %s has composite primary key %v", ErrMapKeyColumn, structType, keys)
+		default:
+			if entity, ok := reflect.Zero(structType).Interface().(Entity); ok {
+				column = entity.PrimaryKey()
+			}
+		}
+		if column == "" {
+			return -1, fmt.Errorf("%w: %s has no primary key", ErrMapKeyColumn, structType)
+		}
+	}
+
+	for idx, columnType := range columns {
+		if columnType.Name() == column {
+			return idx, nil
+		}
+	}
+	if m.caseInsensitive {
+		for idx, columnType := range columns {
+			if strings.EqualFold(columnType.Name(), column) {
+				return idx, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("%w: %s", ErrMapKeyColumn, column)
+}
+
+// mapKey 将扫描得到的值转换为 map 键的类型，value 为 prepare() 中准备的扫描目标。
+func mapKey(value any, keyType reflect.Type, column string) (reflect.Value, error) {
+	var nValue = reflect.ValueOf(value).Elem().Elem()
+	if !nValue.IsValid() {
+		return reflect.Value{}, fmt.Errorf("dbs: map key column %q is NULL", column)
+	}
+
+	if key, ok := convertValue(nValue, keyType); ok {
+		return key, nil
+	}
+	return reflect.Value{}, fmt.Errorf("dbs: cannot use column %q value of type %s as map key of type %s", column, nValue.Type(), keyType)
+}
+
+// convertValue 将 value 转换为 t 类型，只在类型兼容时进行转换，避免如 int64 被转换为 string 的情况。
+func convertValue(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if bytes, ok := value.Interface().([]byte); ok && t.Kind() == reflect.String {
+		value = reflect.ValueOf(string(bytes))
+	}
+
+	switch {
+	case value.Type().AssignableTo(t):
+		return value, true
+	case value.Kind() == t.Kind() || isNumberKind(value.Kind()) && isNumberKind(t.Kind()):
+		if value.Type().ConvertibleTo(t) {
+			return value.Convert(t), true
+		}
+	}
+	return value, false
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
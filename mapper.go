@@ -1,6 +1,7 @@
 package dbs
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -16,10 +17,26 @@ const (
 	kTagSQL            = "sql"
 	kTagValueDisable   = "-"
 	kTagValueDefault   = "default"
+	kTagValuePrefix    = "prefix"
+	kTagValuePK        = "pk"
+	kTagValueReadOnly  = "readonly"
+	kTagValueInsert    = "insert"
+	kTagValueUpdate    = "update"
+	kTagValueOmitEmpty = "omitempty"
+	kTagValueJSON      = "json"
+	kTagValueArray     = "array"
+	kTagValueSet       = "set"
 	kTagValueSeparator = ";"
+
+	// kRelationSeparator 关联结构体的列名分隔符，如 user.id，同时支持使用 __ 分隔，如 user__id
+	kRelationSeparator = "."
+
+	// kMaxRelationDepth 关联结构体的最大嵌套层数，用于避免自引用的结构体无限展开
+	kMaxRelationDepth = 4
 )
 
 var ErrInvalidEncodeValue = errors.New("dbs: encode value must be a non-nil struct or struct pointer")
+var ErrInvalidColumnsValue = errors.New("dbs: columns value must be a struct or struct pointer")
 
 type Mapper interface {
 	Encode(src any) (values []FieldValue, err error)
@@ -27,21 +44,51 @@ type Mapper interface {
 	Decode(rows *sql.Rows, dest any) (int, error)
 }
 
+// ColumnsMapper 是 Mapper 的可选接口，用于获取结构体映射的列名。
+type ColumnsMapper interface {
+	Columns(src any) ([]string, error)
+}
+
+// FieldsMapper 是 Mapper 的可选接口，用于获取结构体映射的字段信息。
+type FieldsMapper interface {
+	Fields(src any) ([]Field, error)
+}
+
+var defaultMapper = NewMapper(kTagSQL)
+
 type mapper struct {
-	tag     string
-	structs atomic.Value // map[reflect.Type]structMetadata
-	mu      *sync.Mutex
+	tag             string
+	naming          NamingStrategy
+	caseInsensitive bool
+	strict          bool
+	json            JSONCodec
+	converters      atomic.Value // map[reflect.Type]converter
+	structs         atomic.Value // map[reflect.Type]structMetadata
+	mu              *sync.Mutex
 }
 
-func NewMapper(tag string) *mapper {
+func NewMapper(tag string, opts ...MapperOption) *mapper {
 	var m = &mapper{}
 	m.tag = tag
+	m.json = stdJSONCodec{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
 	m.structs.Store(make(map[reflect.Type]structMetadata))
+	m.converters.Store(make(map[reflect.Type]converter))
 	m.mu = &sync.Mutex{}
 	return m
 }
 
 func (m *mapper) Encode(src any) (values []FieldValue, err error) {
+	if raw, ok := src.(FieldEncoder); ok {
+		if value := reflect.ValueOf(src); value.Kind() != reflect.Ptr || !value.IsNil() {
+			return raw.EncodeFields(), nil
+		}
+	}
+
 	var srcType, srcValue, nErr = encodeBase(src)
 	if nErr != nil {
 		return nil, nErr
@@ -59,21 +106,95 @@ func (m *mapper) Encode(src any) (values []FieldValue, err error) {
 			continue
 		}
 
+		// 只读字段不参与编码，主键除外（用于生成 WHERE 条件）
+		if field.ReadOnly && !field.PrimaryKey {
+			continue
+		}
+
 		var value, found = findFieldByIndex(srcValue, field.Index)
 		if !found {
 			continue
 		}
-		var useDefault = false
-		var useValue = value.Interface()
-		if field.UseDefault && value.IsZero() {
-			useDefault = true
-			useValue = SQL("DEFAULT")
+		var fieldValue = FieldValue{
+			Name:       column,
+			Value:      value.Interface(),
+			PrimaryKey: field.PrimaryKey,
+			ReadOnly:   field.ReadOnly,
+			InsertOnly: field.InsertOnly,
+			UpdateOnly: field.UpdateOnly,
+		}
+		if field.Codec != nil {
+			if fieldValue.Value, err = field.Codec.encode(column, value); err != nil {
+				return nil, err
+			}
+		}
+		if value.IsZero() {
+			if field.UseDefault {
+				fieldValue.UseDefault = true
+				fieldValue.Value = SQL("DEFAULT")
+			}
+			fieldValue.OmitEmpty = field.OmitEmpty
 		}
-		values = append(values, FieldValue{Name: column, UseDefault: useDefault, Value: useValue})
+		values = append(values, fieldValue)
 	}
 	return values, nil
 }
 
+// Columns 返回结构体映射的列名，src 可以为结构体、结构体指针或者类型为结构体指针的 nil，如 (*User)(nil)。
+func (m *mapper) Columns(src any) ([]string, error) {
+	var mStruct, err = m.structMetadataOf(src)
+	if err != nil {
+		return nil, err
+	}
+	var columns = make([]string, len(mStruct.columns))
+	copy(columns, mStruct.columns)
+	return columns, nil
+}
+
+// Fields 返回结构体映射的字段信息，顺序与 Columns 一致，src 的要求与 Columns 相同。
+func (m *mapper) Fields(src any) ([]Field, error) {
+	var mStruct, err = m.structMetadataOf(src)
+	if err != nil {
+		return nil, err
+	}
+	var fields = make([]Field, 0, len(mStruct.columns))
+	for _, column := range mStruct.columns {
+		var field = mStruct.fields[column]
+		fields = append(fields, Field{
+			Column:     column,
+			Type:       field.Type,
+			Encoding:   fieldEncoding(field.Codec),
+			UseDefault: field.UseDefault,
+			PrimaryKey: field.PrimaryKey,
+			ReadOnly:   field.ReadOnly,
+			InsertOnly: field.InsertOnly,
+			UpdateOnly: field.UpdateOnly,
+			OmitEmpty:  field.OmitEmpty,
+			Nullable:   field.Nullable,
+		})
+	}
+	return fields, nil
+}
+
+func (m *mapper) structMetadataOf(src any) (structMetadata, error) {
+	if src == nil {
+		return structMetadata{}, ErrInvalidColumnsValue
+	}
+	var srcType = reflect.TypeOf(src)
+	for srcType.Kind() == reflect.Ptr {
+		srcType = srcType.Elem()
+	}
+	if srcType.Kind() != reflect.Struct {
+		return structMetadata{}, ErrInvalidColumnsValue
+	}
+
+	var mStruct, ok = m.getStructMetadata(srcType)
+	if !ok {
+		mStruct = m.buildStructMetadata(srcType)
+	}
+	return mStruct, nil
+}
+
 func encodeBase(src any) (reflect.Type, reflect.Value, error) {
 	if src == nil {
 		return nil, reflect.Value{}, ErrInvalidEncodeValue
@@ -98,6 +219,12 @@ func encodeBase(src any) (reflect.Type, reflect.Value, error) {
 }
 
 func (m *mapper) Decode(rows *sql.Rows, dest any) (rowsAffected int, err error) {
+	return m.DecodeContext(context.Background(), rows, dest)
+}
+
+func (m *mapper) DecodeContext(ctx context.Context, rows *sql.Rows, dest any) (rowsAffected int, err error) {
+	var strict = strictFromContext(ctx, m.strict)
+
 	if rows == nil {
 		return rowsAffected, sql.ErrNoRows
 	}
@@ -135,14 +262,18 @@ func (m *mapper) Decode(rows *sql.Rows, dest any) (rowsAffected int, err error)
 
 	destType, destValue = base(destType, destValue)
 
+	if isKeyedMap(destType) {
+		return m.scanKeyedMap(ctx, rows, columns, strict, destType, destValue)
+	}
+
 	if destType.Kind() == reflect.Slice {
 		var oldLen = destValue.Len()
-		if err = m.scanSlice(rows, columns, dest, destType, destValue); err != nil {
+		if err = m.scanSlice(rows, columns, strict, dest, destType, destValue); err != nil {
 			return 0, err
 		}
 		rowsAffected = destValue.Len() - oldLen
 	} else {
-		if err = m.scanOne(rows, columns, dest, destType, destValue); err != nil {
+		if err = m.scanOne(rows, columns, strict, dest, destType, destValue); err != nil {
 			return 0, err
 		}
 		rowsAffected = 1
@@ -150,7 +281,7 @@ func (m *mapper) Decode(rows *sql.Rows, dest any) (rowsAffected int, err error)
 	return rowsAffected, nil
 }
 
-func (m *mapper) prepare(destType reflect.Type, columns []*sql.ColumnType) (fields []*fieldMetadata, values []any) {
+func (m *mapper) prepare(destType reflect.Type, columns []*sql.ColumnType, strict bool) (fields []*fieldMetadata, values []any, err error) {
 	var mStruct, ok = m.getStructMetadata(destType)
 	if !ok {
 		mStruct = m.buildStructMetadata(destType)
@@ -167,13 +298,25 @@ func (m *mapper) prepare(destType reflect.Type, columns []*sql.ColumnType) (fiel
 			values[idx] = &val
 		}
 	}
-	return fields, values
+	if strict && !isValueType(destType) {
+		err = checkColumns(destType, mStruct, columns, fields)
+	}
+	return fields, values, err
 }
 
-func (m *mapper) scanOne(rows *sql.Rows, columns []*sql.ColumnType, dest any, destType reflect.Type, destValue reflect.Value) error {
+func (m *mapper) scanOne(rows *sql.Rows, columns []*sql.ColumnType, strict bool, dest any, destType reflect.Type, destValue reflect.Value) error {
+	switch {
+	case m.scanAsScanner(destType, columns):
+		return rows.Scan(destValue.Addr().Interface())
+	case destType == timeType:
+		return scanIntoValue(rows, destType, destValue)
+	case isColumnScanner(destType):
+		return scanColumns(rows, columnNames(columns), destValue.Addr().Interface().(ColumnScanner))
+	}
+
 	switch destType.Kind() {
 	case reflect.Struct:
-		var fields, values = m.prepare(destType, columns)
+		var fields, values, err = m.prepare(destType, columns, strict)
 		defer func() {
 			for idx, value := range values {
 				var field = fields[idx]
@@ -182,20 +325,16 @@ func (m *mapper) scanOne(rows *sql.Rows, columns []*sql.ColumnType, dest any, de
 				}
 			}
 		}()
+		if err != nil {
+			return err
+		}
 
-		return scanIntoStruct(rows, columns, fields, values, destValue)
+		return scanIntoStruct(rows, columns, fields, values, strict, 0, destValue)
 	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64,
 		reflect.String:
-		var nPointer = reflect.New(reflect.PointerTo(destType))
-		if err := rows.Scan(nPointer.Interface()); err != nil {
-			return err
-		}
-		var nValue = nPointer.Elem().Elem()
-		if nValue.IsValid() {
-			destValue.Set(nValue)
-		}
+		return scanIntoValue(rows, destType, destValue)
 	case reflect.Map:
 		if destType.Key().Kind() == reflect.String {
 			switch destType.Elem().Kind() {
@@ -235,10 +374,9 @@ func (m *mapper) scanOne(rows *sql.Rows, columns []*sql.ColumnType, dest any, de
 	default:
 		return fmt.Errorf("%s is unsupported", destType.Kind())
 	}
-	return nil
 }
 
-func (m *mapper) scanSlice(rows *sql.Rows, columns []*sql.ColumnType, dest any, destType reflect.Type, destValue reflect.Value) error {
+func (m *mapper) scanSlice(rows *sql.Rows, columns []*sql.ColumnType, strict bool, dest any, destType reflect.Type, destValue reflect.Value) error {
 	// 获取 slice 元素类型
 	destType = destType.Elem()
 
@@ -249,9 +387,19 @@ func (m *mapper) scanSlice(rows *sql.Rows, columns []*sql.ColumnType, dest any,
 		destType = destType.Elem()
 	}
 
+	// sql.Scanner 以及 time.Time 直接扫描，不作为结构体处理
+	switch {
+	case m.scanAsScanner(destType, columns):
+		return scanIntoScanners(rows, destType, isPointer, destValue)
+	case destType == timeType:
+		return scanIntoValues(rows, destType, isPointer, destValue)
+	case isColumnScanner(destType):
+		return scanIntoColumnScanners(rows, columns, destType, isPointer, destValue)
+	}
+
 	switch destType.Kind() {
 	case reflect.Struct:
-		var fields, values = m.prepare(destType, columns)
+		var fields, values, err = m.prepare(destType, columns, strict)
 		defer func() {
 			for idx, value := range values {
 				var field = fields[idx]
@@ -260,13 +408,16 @@ func (m *mapper) scanSlice(rows *sql.Rows, columns []*sql.ColumnType, dest any,
 				}
 			}
 		}()
+		if err != nil {
+			return err
+		}
 
 		var nList = make([]reflect.Value, 0, 20)
-		for {
+		for row := 0; ; row++ {
 			var nPointer = reflect.New(destType)
 			var nValue = reflect.Indirect(nPointer)
 
-			if err := scanIntoStruct(rows, columns, fields, values, nValue); err != nil {
+			if err = scanIntoStruct(rows, columns, fields, values, strict, row, nValue); err != nil {
 				return err
 			}
 
@@ -291,31 +442,7 @@ func (m *mapper) scanSlice(rows *sql.Rows, columns []*sql.ColumnType, dest any,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64,
 		reflect.String:
-		var nList = make([]reflect.Value, 0, 20)
-		for {
-			var nPointer = reflect.New(reflect.PointerTo(destType))
-			if err := rows.Scan(nPointer.Interface()); err != nil {
-				return err
-			}
-			var nValue = nPointer.Elem().Elem()
-			if nValue.IsValid() {
-				if isPointer {
-					nList = append(nList, nPointer.Elem())
-				} else {
-					nList = append(nList, nValue)
-				}
-			}
-
-			if !rows.Next() {
-				break
-			}
-		}
-		if err := rows.Err(); err != nil {
-			return err
-		}
-		if len(nList) > 0 {
-			destValue.Set(reflect.Append(destValue, nList...))
-		}
+		return scanIntoValues(rows, destType, isPointer, destValue)
 	case reflect.Map:
 		if destType.Key().Kind() == reflect.String {
 			switch destType.Elem().Kind() {
@@ -436,36 +563,34 @@ func scanIntoMapValue[T any](rows *sql.Rows, useColumnType bool, columns []*sql.
 	return nil
 }
 
-func scanIntoStruct(rows *sql.Rows, columns []*sql.ColumnType, fields []*fieldMetadata, values []any, destValue reflect.Value) error {
-	var isScanner = false
-	if len(columns) == 1 && fields[0] == nil {
-		switch destValue.Addr().Interface().(type) {
-		case time.Time:
-			var val time.Time
-			values[0] = &val
-		case *time.Time:
-			var val *time.Time
-			values[0] = &val
-		case sql.Scanner:
-			values[0] = destValue.Addr().Interface()
-			isScanner = true
-		}
-	}
-
+func scanIntoStruct(rows *sql.Rows, columns []*sql.ColumnType, fields []*fieldMetadata, values []any, strict bool, row int, destValue reflect.Value) error {
 	if err := rows.Scan(values...); err != nil {
 		return err
 	}
 
+	var nullColumns []string
 	for idx := range columns {
 		var field = fields[idx]
 		if field != nil {
 			var value = reflect.ValueOf(values[idx]).Elem().Elem()
-			if value.IsValid() {
+			if !value.IsValid() && strict && !field.Nullable && !field.Relation {
+				nullColumns = append(nullColumns, columns[idx].Name())
+			}
+			if value.IsValid() && field.Codec != nil {
+				if err := field.Codec.decode(columns[idx].Name(), value.Interface(), fieldByIndex(destValue, field.Index)); err != nil {
+					return err
+				}
+			} else if value.IsValid() {
 				fieldByIndex(destValue, fields[idx].Index).Set(value)
+			} else if field.Relation {
+				// 关联结构体为指针时，只有存在非 NULL 的列才会创建，LEFT JOIN 未匹配时保持为 nil
+				if nValue, found := findFieldByIndex(destValue, field.Index); found {
+					nValue.Set(reflect.Zero(field.Type))
+				}
 			} else {
 				fieldByIndex(destValue, fields[idx].Index).Set(reflect.Zero(fields[idx].Type))
 			}
-		} else if len(columns) == 1 && !isScanner {
+		} else if len(columns) == 1 {
 			var value = reflect.ValueOf(values[idx]).Elem().Elem()
 			if !value.IsValid() {
 				destValue.Set(reflect.Zero(destValue.Type()))
@@ -478,6 +603,77 @@ func scanIntoStruct(rows *sql.Rows, columns []*sql.ColumnType, fields []*fieldMe
 			}
 		}
 	}
+	if len(nullColumns) > 0 {
+		return &DecodeError{Type: destValue.Type(), NullColumns: nullColumns, Row: row}
+	}
+	return nil
+}
+
+func scanIntoValue(rows *sql.Rows, destType reflect.Type, destValue reflect.Value) error {
+	var nPointer = reflect.New(reflect.PointerTo(destType))
+	if err := rows.Scan(nPointer.Interface()); err != nil {
+		return err
+	}
+	var nValue = nPointer.Elem().Elem()
+	if nValue.IsValid() {
+		destValue.Set(nValue)
+	}
+	return nil
+}
+
+func scanIntoValues(rows *sql.Rows, destType reflect.Type, isPointer bool, destValue reflect.Value) error {
+	var nList = make([]reflect.Value, 0, 20)
+	for {
+		var nPointer = reflect.New(reflect.PointerTo(destType))
+		if err := rows.Scan(nPointer.Interface()); err != nil {
+			return err
+		}
+		var nValue = nPointer.Elem().Elem()
+		if nValue.IsValid() {
+			if isPointer {
+				nList = append(nList, nPointer.Elem())
+			} else {
+				nList = append(nList, nValue)
+			}
+		}
+
+		if !rows.Next() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(nList) > 0 {
+		destValue.Set(reflect.Append(destValue, nList...))
+	}
+	return nil
+}
+
+// scanIntoScanners 将每一行扫描到实现了 sql.Scanner 的元素中，NULL 值由元素自身处理，因此不会被忽略。
+func scanIntoScanners(rows *sql.Rows, destType reflect.Type, isPointer bool, destValue reflect.Value) error {
+	var nList = make([]reflect.Value, 0, 20)
+	for {
+		var nPointer = reflect.New(destType)
+		if err := rows.Scan(nPointer.Interface()); err != nil {
+			return err
+		}
+		if isPointer {
+			nList = append(nList, nPointer)
+		} else {
+			nList = append(nList, nPointer.Elem())
+		}
+
+		if !rows.Next() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(nList) > 0 {
+		destValue.Set(reflect.Append(destValue, nList...))
+	}
 	return nil
 }
 
@@ -544,8 +740,10 @@ func (m *mapper) setStructMetadata(key reflect.Type, value structMetadata) {
 }
 
 type element struct {
-	Type  reflect.Type
-	Index []int
+	Type   reflect.Type
+	Index  []int
+	Prefix string
+	Depth  int
 }
 
 func (m *mapper) buildStructMetadata(destType reflect.Type) structMetadata {
@@ -583,19 +781,27 @@ func (m *mapper) buildStructMetadata(destType reflect.Type) structMetadata {
 				continue
 			}
 
+			if tag == "" && m.naming != nil && (isValueType(fieldStruct.Type) || !isStructType(fieldStruct.Type)) {
+				tag = m.naming(fieldStruct.Name)
+			}
+
 			if tag == "" {
 				if fieldStruct.Type.Kind() == reflect.Ptr && fieldStruct.Type.Elem().Kind() == reflect.Struct {
 					queue = append(queue, element{
-						Type:  fieldStruct.Type.Elem(),
-						Index: append(current.Index, i),
+						Type:   fieldStruct.Type.Elem(),
+						Index:  appendIndex(current.Index, i),
+						Prefix: current.Prefix,
+						Depth:  current.Depth,
 					})
 					continue
 				}
 
 				if fieldStruct.Type.Kind() == reflect.Struct {
 					queue = append(queue, element{
-						Type:  fieldStruct.Type,
-						Index: append(current.Index, i),
+						Type:   fieldStruct.Type,
+						Index:  appendIndex(current.Index, i),
+						Prefix: current.Prefix,
+						Depth:  current.Depth,
 					})
 					continue
 				}
@@ -607,6 +813,7 @@ func (m *mapper) buildStructMetadata(destType reflect.Type) structMetadata {
 			if fieldName == "" {
 				continue
 			}
+			fieldName = current.Prefix + fieldName
 			if _, ok = fields[fieldName]; ok {
 				continue
 			}
@@ -616,17 +823,62 @@ func (m *mapper) buildStructMetadata(destType reflect.Type) structMetadata {
 				tagMap[strings.ToLower(strings.TrimSpace(tagValue))] = true
 			}
 
+			var codec = m.fieldCodec(tagMap, fieldStruct.Type)
+
+			if tagMap[kTagValuePrefix] && codec == nil {
+				// 关联结构体，其字段通过 "前缀.列名" 或者 "前缀__列名" 进行映射
+				var relationType = fieldStruct.Type
+				if relationType.Kind() == reflect.Ptr {
+					relationType = relationType.Elem()
+				}
+				if relationType.Kind() == reflect.Struct {
+					if current.Depth < kMaxRelationDepth {
+						queue = append(queue, element{
+							Type:   relationType,
+							Index:  appendIndex(current.Index, i),
+							Prefix: fieldName + kRelationSeparator,
+							Depth:  current.Depth + 1,
+						})
+					}
+					continue
+				}
+			}
+
 			var field = &fieldMetadata{}
-			field.Index = append(current.Index, i)
+			field.Index = appendIndex(current.Index, i)
 			field.Type = fieldStruct.Type
 			field.ValuePool = getValuePool(field.Type)
+			field.Nullable = isNullable(field.Type)
+			if codec != nil {
+				// 先扫描为 fieldCodec 需要的类型，再使用 fieldCodec 解码
+				field.Codec = codec
+				field.ValuePool = getValuePool(codec.scanType())
+			}
 			field.UseDefault = tagMap[kTagValueDefault]
+			field.PrimaryKey = tagMap[kTagValuePK]
+			field.ReadOnly = tagMap[kTagValueReadOnly]
+			// 同时设置 insert 和 update 等同于都不设置
+			field.InsertOnly = tagMap[kTagValueInsert] && !tagMap[kTagValueUpdate]
+			field.UpdateOnly = tagMap[kTagValueUpdate] && !tagMap[kTagValueInsert]
+			field.OmitEmpty = tagMap[kTagValueOmitEmpty]
+			field.Relation = current.Depth > 0
 			fields[fieldName] = field
-			columns = append(columns, fieldName)
+			if !field.Relation {
+				columns = append(columns, fieldName)
+			}
 		}
 	}
 	mStruct.columns = columns
 	mStruct.fields = fields
+	if m.caseInsensitive {
+		mStruct.foldedFields = make(map[string]*fieldMetadata, len(fields))
+		for name, field := range fields {
+			var folded = strings.ToLower(name)
+			if _, exists := mStruct.foldedFields[folded]; !exists || name == folded {
+				mStruct.foldedFields[folded] = field
+			}
+		}
+	}
 
 	m.setStructMetadata(destType, mStruct)
 	m.mu.Unlock()
@@ -634,20 +886,113 @@ func (m *mapper) buildStructMetadata(destType reflect.Type) structMetadata {
 	return mStruct
 }
 
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+func isScannerType(fieldType reflect.Type) bool {
+	return reflect.PointerTo(fieldType).Implements(scannerType)
+}
+
+// scanAsScanner 判断是否将查询结果直接扫描到实现了 sql.Scanner 的 destType 中。
+//
+// destType 为结构体时，只有查询结果为一列且该列没有映射到结构体字段时才直接扫描，否则按照列映射到结构体字段。
+func (m *mapper) scanAsScanner(destType reflect.Type, columns []*sql.ColumnType) bool {
+	if !isScannerType(destType) {
+		return false
+	}
+	if destType.Kind() != reflect.Struct {
+		return true
+	}
+	if len(columns) != 1 {
+		return false
+	}
+	var mStruct, ok = m.getStructMetadata(destType)
+	if !ok {
+		mStruct = m.buildStructMetadata(destType)
+	}
+	return mStruct.Field(columns[0].Name()) == nil
+}
+
+func isStructType(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct
+}
+
+// isValueType 判断结构体类型是否应该作为一个整体映射到列，如 time.Time、sql.NullString 等。
+func isValueType(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == timeType {
+		return true
+	}
+	return reflect.PointerTo(fieldType).Implements(scannerType) || fieldType.Implements(valuerType)
+}
+
+func appendIndex(index []int, i int) []int {
+	var nIndex = make([]int, len(index)+1)
+	copy(nIndex, index)
+	nIndex[len(index)] = i
+	return nIndex
+}
+
 type fieldMetadata struct {
 	Index      []int
 	Type       reflect.Type
 	ValuePool  *sync.Pool
 	UseDefault bool
+	PrimaryKey bool
+	ReadOnly   bool
+	InsertOnly bool
+	UpdateOnly bool
+	OmitEmpty  bool
+	Nullable   bool
+	Relation   bool       // 字段属于关联结构体，不参与 Encode
+	Codec      fieldCodec // 设置了 json、array、set 标签选项或者注册了转换函数的字段使用的编解码器
+}
+
+// fieldCodec 用于在字段和数据库的值之间进行转换。
+type fieldCodec interface {
+	encode(column string, value reflect.Value) (any, error)
+
+	// decode 的 src 为扫描得到的非 NULL 值，类型由 scanType 决定
+	decode(column string, src any, dest reflect.Value) error
+
+	scanType() reflect.Type
+}
+
+func (m *mapper) fieldCodec(tagMap map[string]bool, fieldType reflect.Type) fieldCodec {
+	switch {
+	case tagMap[kTagValueJSON]:
+		return jsonCodec{codec: m.json}
+	case tagMap[kTagValueArray]:
+		return arrayCodec{}
+	case tagMap[kTagValueSet]:
+		return setCodec{}
+	}
+	return m.converterCodec(fieldType)
 }
 
 type structMetadata struct {
-	columns []string
-	fields  map[string]*fieldMetadata
+	columns      []string
+	fields       map[string]*fieldMetadata
+	foldedFields map[string]*fieldMetadata // 列名转换为小写后的映射，仅在忽略大小写时使用
 }
 
 func (s structMetadata) Field(name string) *fieldMetadata {
 	var field = s.fields[name]
+	if field == nil && strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", kRelationSeparator)
+		field = s.fields[name]
+	}
+	if field == nil && s.foldedFields != nil {
+		field = s.foldedFields[strings.ToLower(name)]
+	}
 	return field
 }
 
@@ -666,4 +1011,19 @@ type FieldValue struct {
 	Name       string
 	Value      any
 	UseDefault bool
+	PrimaryKey bool
+	ReadOnly   bool // 只读字段，只有主键会被编码
+	InsertOnly bool
+	UpdateOnly bool
+	OmitEmpty  bool // 字段设置了 omitempty 并且值为零值
+}
+
+// Insertable 判断字段是否可以用于 INSERT 语句。
+func (fv FieldValue) Insertable() bool {
+	return !fv.ReadOnly && !fv.UpdateOnly
+}
+
+// Updatable 判断字段是否可以用于 UPDATE 语句的 SET 部分，主键不会被更新。
+func (fv FieldValue) Updatable() bool {
+	return !fv.ReadOnly && !fv.InsertOnly && !fv.PrimaryKey
 }
@@ -0,0 +1,273 @@
+package dbs_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/internal/dbstest"
+)
+
+func TestSnakeCase(t *testing.T) {
+	var tests = []struct {
+		Name   string
+		Expect string
+	}{
+		{Name: "Id", Expect: "id"},
+		{Name: "UserID", Expect: "user_id"},
+		{Name: "CreatedAt", Expect: "created_at"},
+		{Name: "HTTPServer", Expect: "http_server"},
+		{Name: "Address2Line", Expect: "address2_line"},
+		{Name: "already_snake", Expect: "already_snake"},
+	}
+
+	for _, test := range tests {
+		if actual := dbs.SnakeCase(test.Name); actual != test.Expect {
+			t.Fatalf("期望列名: %s, 实际列名: %s", test.Expect, actual)
+		}
+	}
+}
+
+type NamingBase struct {
+	ID int64
+}
+
+type namingUser struct {
+	NamingBase
+	UserName  string `sql:"name"`
+	Nickname  sql.NullString
+	CreatedAt time.Time
+	Ignore    string `sql:"-"`
+	internal  string
+}
+
+func TestMapper_NamingStrategy(t *testing.T) {
+	var tests = []struct {
+		Mapper dbs.Mapper
+		Expect []string
+	}{
+		{
+			Mapper: dbs.NewMapper("sql"),
+			Expect: []string{"name"},
+		},
+		{
+			Mapper: dbs.NewMapper("sql", dbs.WithNamingStrategy(dbs.SnakeCase)),
+			Expect: []string{"name", "nickname", "created_at", "id"},
+		},
+		{
+			Mapper: dbs.NewMapper("sql", dbs.WithNamingStrategy(dbs.LowerCase)),
+			Expect: []string{"name", "nickname", "createdat", "id"},
+		},
+		{
+			Mapper: dbs.NewMapper("sql", dbs.WithNamingStrategy(dbs.Identity)),
+			Expect: []string{"name", "Nickname", "CreatedAt", "ID"},
+		},
+	}
+
+	for _, test := range tests {
+		var columns, err = dbs.ColumnsOf(test.Mapper, namingUser{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(columns, test.Expect) {
+			t.Fatalf("期望列: %v, 实际列: %v", test.Expect, columns)
+		}
+	}
+}
+
+type encodeUser struct {
+	Id        int64  `sql:"id;pk;readonly"`
+	TenantId  int64  `sql:"tenant_id;pk"`
+	Name      string `sql:"name;omitempty"`
+	Version   int64  `sql:"version;readonly"`
+	CreatedAt int64  `sql:"created_at;insert"`
+	UpdatedAt int64  `sql:"updated_at;update"`
+	Status    int    `sql:"status;default"`
+}
+
+func TestMapper_Encode(t *testing.T) {
+	var mapper = dbs.NewMapper("sql")
+
+	var fieldValues, err = mapper.Encode(&encodeUser{Id: 1, TenantId: 2, CreatedAt: 3, UpdatedAt: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = []dbs.FieldValue{
+		{Name: "id", Value: int64(1), PrimaryKey: true, ReadOnly: true},
+		{Name: "tenant_id", Value: int64(2), PrimaryKey: true},
+		{Name: "name", Value: "", OmitEmpty: true},
+		{Name: "created_at", Value: int64(3), InsertOnly: true},
+		{Name: "updated_at", Value: int64(4), UpdateOnly: true},
+		{Name: "status", Value: dbs.SQL("DEFAULT"), UseDefault: true},
+	}
+	if !reflect.DeepEqual(fieldValues, expect) {
+		t.Fatalf("期望字段: %+v, 实际字段: %+v", expect, fieldValues)
+	}
+
+	var insertable, updatable []string
+	for _, fieldValue := range fieldValues {
+		if fieldValue.Insertable() {
+			insertable = append(insertable, fieldValue.Name)
+		}
+		if fieldValue.Updatable() {
+			updatable = append(updatable, fieldValue.Name)
+		}
+	}
+	if expect := []string{"tenant_id", "name", "created_at", "status"}; !reflect.DeepEqual(insertable, expect) {
+		t.Fatalf("期望 INSERT 字段: %v, 实际 INSERT 字段: %v", expect, insertable)
+	}
+	if expect := []string{"name", "updated_at", "status"}; !reflect.DeepEqual(updatable, expect) {
+		t.Fatalf("期望 UPDATE 字段: %v, 实际 UPDATE 字段: %v", expect, updatable)
+	}
+}
+
+func TestFields(t *testing.T) {
+	var fields = dbs.Fields[encodeUser]()
+	if len(fields) != 7 {
+		t.Fatalf("期望字段数量: %d, 实际字段数量: %d", 7, len(fields))
+	}
+
+	var id = fields[0]
+	if id.Column != "id" || id.Type != reflect.TypeOf(int64(0)) || !id.PrimaryKey || !id.ReadOnly || id.Nullable {
+		t.Fatalf("id 字段不符合预期: %+v", id)
+	}
+	if status := fields[6]; status.Column != "status" || !status.UseDefault || status.Encoding != dbs.EncodingNone {
+		t.Fatalf("status 字段不符合预期: %+v", status)
+	}
+}
+
+func TestDuplicateKeyError(t *testing.T) {
+	var err error = &dbs.DuplicateKeyError{Column: "id", Key: int64(1), Row: 2}
+	if !errors.Is(err, dbs.ErrDuplicateKey) {
+		t.Fatal("期望 errors.Is(err, dbs.ErrDuplicateKey) 返回 true")
+	}
+
+	var dErr *dbs.DuplicateKeyError
+	if !errors.As(err, &dErr) || dErr.Row != 2 {
+		t.Fatalf("期望获取到 *DuplicateKeyError, 实际: %v", err)
+	}
+	if expect := "dbs: duplicate map key: id = 1 (row: 2)"; err.Error() != expect {
+		t.Fatalf("期望错误信息: %s, 实际错误信息: %s", expect, err.Error())
+	}
+}
+
+type jsonProfile struct {
+	City string `json:"city"`
+}
+
+type jsonUser struct {
+	Id      int64             `sql:"id"`
+	Profile jsonProfile       `sql:"profile;json"`
+	Extra   *jsonProfile      `sql:"extra;json"`
+	Tags    []string          `sql:"tags;json"`
+	Attrs   map[string]string `sql:"attrs;json"`
+}
+
+type constCodec struct{}
+
+func (constCodec) Marshal(v any) ([]byte, error) {
+	return []byte(`"codec"`), nil
+}
+
+func (constCodec) Unmarshal(data []byte, v any) error {
+	return nil
+}
+
+func TestMapper_EncodeJSON(t *testing.T) {
+	var tests = []struct {
+		Mapper dbs.Mapper
+		User   jsonUser
+		Expect []any
+	}{
+		{
+			Mapper: dbs.NewMapper("sql"),
+			User:   jsonUser{Id: 1, Profile: jsonProfile{City: "a"}, Tags: []string{"x"}},
+			Expect: []any{int64(1), `{"city":"a"}`, nil, `["x"]`, nil},
+		},
+		{
+			Mapper: dbs.NewMapper("sql"),
+			User:   jsonUser{Id: 2, Extra: &jsonProfile{City: "b"}, Tags: []string{}, Attrs: map[string]string{"k": "v"}},
+			Expect: []any{int64(2), `{"city":""}`, `{"city":"b"}`, `[]`, `{"k":"v"}`},
+		},
+		{
+			Mapper: dbs.NewMapper("sql", dbs.WithJSONCodec(constCodec{})),
+			User:   jsonUser{Id: 3},
+			Expect: []any{int64(3), `"codec"`, nil, nil, nil},
+		},
+	}
+
+	for _, test := range tests {
+		var fieldValues, err = test.Mapper.Encode(test.User)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values = make([]any, 0, len(fieldValues))
+		for _, fieldValue := range fieldValues {
+			values = append(values, fieldValue.Value)
+		}
+		if !reflect.DeepEqual(values, test.Expect) {
+			t.Fatalf("期望值: %v, 实际值: %v", test.Expect, values)
+		}
+	}
+}
+
+// scannerUser 实现了 sql.Scanner，查询多列时按照列映射到结构体字段，查询未映射的单列时使用 Scan() 方法。
+type scannerUser struct {
+	Id   int64  `sql:"id"`
+	Name string `sql:"name"`
+}
+
+func (u *scannerUser) Scan(src any) error {
+	u.Name = "scan:" + string(src.([]byte))
+	return nil
+}
+
+func TestMapper_ScannerStruct(t *testing.T) {
+	var ctx = context.Background()
+	var db = openMemoryDB(t, 2)
+
+	var user, err = dbs.Query[scannerUser](ctx, db, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (scannerUser{Id: 0, Name: "name0"}); user != expect {
+		t.Fatalf("期望: %+v, 实际: %+v", expect, user)
+	}
+
+	users, err := dbs.Query[[]*scannerUser](ctx, db, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[1].Name != "name1" {
+		t.Fatalf("期望: %s, 实际: %+v", "name1", users)
+	}
+
+	cursor, err := dbs.Iterate[scannerUser](ctx, db, dbs.SQL("SELECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		if expect := "name" + strconv.Itoa(cursor.Total()-1); cursor.Value().Name != expect {
+			t.Fatalf("期望: %s, 实际: %s", expect, cursor.Value().Name)
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var single = dbstest.Open(t, dbstest.NewDriver(map[string]dbstest.Result{"": {Columns: []string{"email"}, Rows: [][]driver.Value{{[]byte("a")}}}}))
+	if user, err = dbs.Query[scannerUser](ctx, single, "SELECT"); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "scan:a"; user.Name != expect {
+		t.Fatalf("期望: %s, 实际: %s", expect, user.Name)
+	}
+}
@@ -0,0 +1,194 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/smartwalle/dbs"
+)
+
+// Locker 用于防止多个进程同时执行迁移，table 为历史表的名称，不同的历史表使用不同的锁。
+//
+// 默认根据 dbs.DialectName() 选择：
+//   - mysql 使用 GET_LOCK()，等待直到获取锁；
+//   - postgres 使用 pg_advisory_lock()，等待直到获取锁；
+//   - 其它数据库在 <table>_lock 表中插入一行数据，已经存在时返回 ErrLocked，进程异常退出之后需要手动删除该行数据。
+//
+// mysql 以及 postgres 的锁属于连接，迁移期间会一直占用一个连接，执行迁移还需要另外的连接，
+// db 允许的最大连接数（SetMaxOpenConns）为 1 时返回 ErrLockConnection，避免一直等待。
+type Locker interface {
+	Lock(ctx context.Context, db *dbs.DB, table string) (unlock func(ctx context.Context) error, err error)
+}
+
+func lockerOf(dialect dbs.Dialect) Locker {
+	switch dbs.DialectName(dialect) {
+	case "mysql":
+		return mysqlLocker{}
+	case "postgres":
+		return postgresLocker{}
+	}
+	return tableLocker{}
+}
+
+// lockConn 返回加锁使用的连接，mysqlLocker 以及 postgresLocker 使用的锁属于连接，加锁和解锁需要在同一个连接上执行。
+func lockConn(ctx context.Context, db *dbs.DB) (*sql.Conn, error) {
+	if db.DB().Stats().MaxOpenConnections == 1 {
+		return nil, ErrLockConnection
+	}
+	return db.DB().Conn(ctx)
+}
+
+type mysqlLocker struct {
+}
+
+func (mysqlLocker) Lock(ctx context.Context, db *dbs.DB, table string) (func(ctx context.Context) error, error) {
+	var conn, err = lockConn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var name = "dbs_migrate." + table
+	var acquired sql.NullInt64
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", name).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if acquired.Int64 != 1 {
+		conn.Close()
+		return nil, ErrLocked
+	}
+
+	return func(ctx context.Context) error {
+		defer conn.Close()
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", name)
+		return err
+	}, nil
+}
+
+type postgresLocker struct {
+}
+
+func (postgresLocker) Lock(ctx context.Context, db *dbs.DB, table string) (func(ctx context.Context) error, error) {
+	var conn, err = lockConn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var hash = fnv.New64a()
+	hash.Write([]byte("dbs_migrate." + table))
+	var key = int64(hash.Sum64())
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		defer conn.Close()
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+		return err
+	}, nil
+}
+
+type tableLocker struct {
+}
+
+func (tableLocker) Lock(ctx context.Context, db *dbs.DB, table string) (func(ctx context.Context) error, error) {
+	var lockTable = table + "_lock"
+
+	var cb = dbs.NewCreateTableBuilder()
+	cb.UseSession(db)
+	cb.Table(lockTable)
+	cb.IfNotExists()
+	cb.Column(dbs.ColumnDef{Name: "id", Type: "INTEGER", NotNull: true, PrimaryKey: true})
+	if _, err := cb.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	var ib = dbs.NewInsertBuilder()
+	ib.UseSession(db)
+	ib.Table(lockTable)
+	ib.Columns("id")
+	ib.Values(1)
+	if _, err := ib.Exec(ctx); err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		var rb = dbs.NewDeleteBuilder()
+		rb.UseSession(db)
+		rb.Table(lockTable)
+		rb.Where("id = ?", 1)
+		_, err := rb.Exec(ctx)
+		return err
+	}, nil
+}
+
+const (
+	// kMySQLDuplicateEntry 为 MySQL 的 ER_DUP_ENTRY
+	kMySQLDuplicateEntry = 1062
+
+	// kPostgresUniqueViolation 为 PostgreSQL 的 unique_violation
+	kPostgresUniqueViolation = "23505"
+
+	// kSQLiteConstraintPrimaryKey 以及 kSQLiteConstraintUnique 为 SQLite 的 SQLITE_CONSTRAINT_PRIMARYKEY 以及 SQLITE_CONSTRAINT_UNIQUE
+	kSQLiteConstraintPrimaryKey = 1555
+	kSQLiteConstraintUnique     = 2067
+)
+
+// isUniqueViolation 判断插入锁表的错误是否为违反唯一约束（锁已经被其它进程持有），其它错误（如连接失败）直接返回。
+//
+// 为了不依赖具体的驱动，与 Dialect 的 Retryable() 一样只根据错误链中的错误码判断：
+//   - SQLState() 方法（github.com/lib/pq、github.com/jackc/pgx）；
+//   - Number 字段（github.com/go-sql-driver/mysql）；
+//   - ExtendedCode 字段（github.com/mattn/go-sqlite3）或者 Code() 方法（modernc.org/sqlite）返回的扩展错误码。
+func isUniqueViolation(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if state, ok := err.(interface{ SQLState() string }); ok {
+			return state.SQLState() == kPostgresUniqueViolation
+		}
+		if coder, ok := err.(interface{ Code() int }); ok {
+			return isSQLiteUniqueCode(int64(coder.Code()))
+		}
+
+		var value = reflect.ValueOf(err)
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			continue
+		}
+		if number, ok := intField(value, "Number"); ok {
+			return number == kMySQLDuplicateEntry
+		}
+		if code, ok := intField(value, "ExtendedCode"); ok {
+			return isSQLiteUniqueCode(code)
+		}
+	}
+	return false
+}
+
+func isSQLiteUniqueCode(code int64) bool {
+	return code == kSQLiteConstraintPrimaryKey || code == kSQLiteConstraintUnique
+}
+
+func intField(value reflect.Value, name string) (int64, bool) {
+	var field = value.FieldByName(name)
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), true
+	}
+	return 0, false
+}
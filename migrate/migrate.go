@@ -0,0 +1,443 @@
+// Package migrate 提供基于版本号的数据库迁移，迁移可以是接收 *dbs.Tx 的 Go 函数，也可以是通过 embed.FS 嵌入的 SQL 文件。
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	var m = migrate.New(db)
+//	if err := m.AddFS(migrations, "migrations"); err != nil {
+//		return err
+//	}
+//	m.Add(3, "backfill_nickname", backfillNickname, nil)
+//	applied, err := m.Up(ctx)
+//
+// 每一个迁移在单独的事务中执行，执行成功之后记录到历史表（默认为 schema_migrations）中。
+// 需要注意 MySQL 的 DDL 会隐式提交事务，执行失败时已经执行的 DDL 不会回滚。
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/smartwalle/dbs"
+)
+
+var (
+	ErrDuplicateVersion = errors.New("migrate: duplicate version")
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+	ErrUnknownVersion   = errors.New("migrate: applied version not found")
+	ErrIrreversible     = errors.New("migrate: migration has no down")
+	ErrLocked           = errors.New("migrate: locked by another runner")
+	ErrLockConnection   = errors.New("migrate: connection-scoped lock requires at least 2 open connections")
+)
+
+const kDefaultTable = "schema_migrations"
+
+// Func 为 Go 函数形式的迁移，ctx 已经通过 tx.WithContext() 绑定了事务。
+type Func func(ctx context.Context, tx *dbs.Tx) error
+
+type Migration struct {
+	Version int64
+	Name    string
+
+	// Checksum 为 SQL 文件迁移中 up 文件内容的 SHA-256，Go 函数迁移为空，不参与校验
+	Checksum string
+
+	up   Func
+	down Func
+
+	// upSQL 以及 downSQL 为 SQL 文件迁移中按照分号拆分之后的语句，用于 dry run
+	upSQL   []string
+	downSQL []string
+	fromSQL bool
+}
+
+// Reversible 判断迁移是否可以回滚。
+func (m *Migration) Reversible() bool {
+	return m.down != nil
+}
+
+// Status 为迁移的执行状态。
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+
+	Missing  bool // 历史表中存在，但是没有找到对应的迁移
+	Modified bool // 执行之后 SQL 文件的内容发生了变化
+}
+
+type Option func(m *Migrator)
+
+// WithTable 指定历史表的名称，默认为 schema_migrations。
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		if table != "" {
+			m.table = table
+		}
+	}
+}
+
+// WithLocker 替换默认的 Locker，默认根据 dbs.DialectName() 选择，参考 Locker。
+func WithLocker(locker Locker) Option {
+	return func(m *Migrator) {
+		m.locker = locker
+	}
+}
+
+// WithDryRun 不执行迁移，只将需要执行的 SQL 写入 w，写入历史表的语句通过 dbs.Explain 生成。
+//
+// Go 函数形式的迁移无法获取 SQL，只会写入注释。dry run 不会创建历史表，也不会加锁。
+func WithDryRun(w io.Writer) Option {
+	return func(m *Migrator) {
+		m.dryRun = w
+	}
+}
+
+type Migrator struct {
+	db         *dbs.DB
+	table      string
+	locker     Locker
+	dryRun     io.Writer
+	migrations map[int64]*Migration
+}
+
+func New(db *dbs.DB, opts ...Option) *Migrator {
+	var m = &Migrator{}
+	m.db = db
+	m.table = kDefaultTable
+	m.migrations = make(map[int64]*Migration)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	if m.locker == nil {
+		m.locker = lockerOf(db.Dialect())
+	}
+	return m
+}
+
+// Add 添加 Go 函数形式的迁移，down 为 nil 时迁移不能回滚。
+func (m *Migrator) Add(version int64, name string, up, down Func) error {
+	if up == nil {
+		return fmt.Errorf("migrate: migration %d must specify an up function", version)
+	}
+	return m.add(&Migration{Version: version, Name: name, up: up, down: down})
+}
+
+func (m *Migrator) add(migration *Migration) error {
+	if migration.Version <= 0 {
+		return fmt.Errorf("migrate: invalid version %d", migration.Version)
+	}
+	if _, exists := m.migrations[migration.Version]; exists {
+		return fmt.Errorf("%w: %d", ErrDuplicateVersion, migration.Version)
+	}
+	m.migrations[migration.Version] = migration
+	return nil
+}
+
+// Migrations 返回按照版本号升序排列的迁移。
+func (m *Migrator) Migrations() []*Migration {
+	var migrations = make([]*Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations
+}
+
+// Up 按照版本号升序执行所有没有执行过的迁移，返回执行的迁移。
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	return m.UpTo(ctx, math.MaxInt64)
+}
+
+// UpTo 执行版本号小于等于 version 并且没有执行过的迁移。
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]*Migration, error) {
+	return m.run(ctx, func(history map[int64]*record) ([]*Migration, error) {
+		var plan []*Migration
+		for _, migration := range m.Migrations() {
+			if _, applied := history[migration.Version]; !applied && migration.Version <= version {
+				plan = append(plan, migration)
+			}
+		}
+		return plan, nil
+	}, true)
+}
+
+// Down 回滚最后一个执行的迁移。
+func (m *Migrator) Down(ctx context.Context) ([]*Migration, error) {
+	return m.down(ctx, 0, 1)
+}
+
+// DownTo 按照版本号降序回滚所有版本号大于 version 的迁移，version 为 0 时回滚所有的迁移。
+func (m *Migrator) DownTo(ctx context.Context, version int64) ([]*Migration, error) {
+	return m.down(ctx, version, 0)
+}
+
+func (m *Migrator) down(ctx context.Context, version int64, limit int) ([]*Migration, error) {
+	return m.run(ctx, func(history map[int64]*record) ([]*Migration, error) {
+		var versions = make([]int64, 0, len(history))
+		for applied := range history {
+			if applied > version {
+				versions = append(versions, applied)
+			}
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		if limit > 0 && len(versions) > limit {
+			versions = versions[:limit]
+		}
+
+		var plan = make([]*Migration, 0, len(versions))
+		for _, applied := range versions {
+			var migration, ok = m.migrations[applied]
+			if !ok {
+				return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, applied)
+			}
+			if !migration.Reversible() {
+				return nil, fmt.Errorf("%w: %d %s", ErrIrreversible, migration.Version, migration.Name)
+			}
+			plan = append(plan, migration)
+		}
+		return plan, nil
+	}, false)
+}
+
+// Status 返回所有迁移以及历史表中记录的执行状态，按照版本号升序排列。
+//
+// 读取执行时间需要驱动支持将时间类型扫描为 time.Time，如 MySQL 需要在 DSN 中设置 parseTime=true。
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var history, err = m.history(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.Migrations() {
+		var status = Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := history[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = migration.Checksum != "" && record.Checksum != "" && migration.Checksum != record.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range history {
+		if _, ok := m.migrations[version]; !ok {
+			statuses = append(statuses, Status{Version: version, Name: record.Name, Applied: true, AppliedAt: record.AppliedAt, Missing: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+type planFunc func(history map[int64]*record) ([]*Migration, error)
+
+func (m *Migrator) run(ctx context.Context, plan planFunc, up bool) (migrations []*Migration, err error) {
+	if m.dryRun != nil {
+		return m.explain(ctx, plan, up)
+	}
+
+	if _, err = m.createTable().Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	unlock, err := m.locker.Lock(ctx, m.db, m.table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if uErr := unlock(context.Background()); uErr != nil && err == nil {
+			err = uErr
+		}
+	}()
+
+	// 加锁之后再读取历史记录，避免重复执行其它进程已经执行过的迁移
+	history, err := m.history(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.verify(history); err != nil {
+		return nil, err
+	}
+	if migrations, err = plan(history); err != nil {
+		return nil, err
+	}
+
+	for idx, migration := range migrations {
+		if err = m.apply(ctx, migration, up); err != nil {
+			return migrations[:idx], err
+		}
+	}
+	return migrations, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration *Migration, up bool) (err error) {
+	var fn = migration.up
+	if !up {
+		fn = migration.down
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			err = fmt.Errorf("migrate: %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}()
+
+	if err = fn(tx.WithContext(ctx), tx); err != nil {
+		return err
+	}
+
+	if up {
+		_, err = m.insertHistory(migration).UseSession(tx).Exec(ctx)
+	} else {
+		_, err = m.deleteHistory(migration).UseSession(tx).Exec(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// verify 检查已经执行的 SQL 文件迁移的内容是否发生了变化。
+func (m *Migrator) verify(history map[int64]*record) error {
+	for version, record := range history {
+		var migration, ok = m.migrations[version]
+		if !ok || migration.Checksum == "" || record.Checksum == "" {
+			continue
+		}
+		if migration.Checksum != record.Checksum {
+			return fmt.Errorf("%w: %d %s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// explain 将需要执行的 SQL 写入 dryRun，历史表不存在时视为没有执行过任何迁移。
+func (m *Migrator) explain(ctx context.Context, plan planFunc, up bool) ([]*Migration, error) {
+	var history, err = m.history(ctx, false)
+	if err != nil {
+		query, _, qErr := m.createTable().SQL()
+		if qErr != nil {
+			return nil, qErr
+		}
+		if _, err = fmt.Fprintf(m.dryRun, "-- history table is not readable: %v\n%s;\n\n", err, query); err != nil {
+			return nil, err
+		}
+		history = map[int64]*record{}
+	}
+	if err = m.verify(history); err != nil {
+		return nil, err
+	}
+
+	migrations, err := plan(history)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		// 使用 db 的 Dialect 生成占位符，与实际执行的语句保持一致
+		var direction, statements, clause = "up", migration.upSQL, dbs.SQLClause(m.insertHistory(migration).UseDialect(m.db.Dialect()))
+		if !up {
+			direction, statements, clause = "down", migration.downSQL, m.deleteHistory(migration).UseDialect(m.db.Dialect())
+		}
+
+		if _, err = fmt.Fprintf(m.dryRun, "-- %d %s (%s)\n", migration.Version, migration.Name, direction); err != nil {
+			return nil, err
+		}
+		if !migration.fromSQL {
+			if _, err = fmt.Fprintf(m.dryRun, "-- go migration, SQL is not available in dry run\n"); err != nil {
+				return nil, err
+			}
+		}
+		for _, statement := range statements {
+			if _, err = fmt.Fprintf(m.dryRun, "%s;\n", statement); err != nil {
+				return nil, err
+			}
+		}
+
+		query, err := dbs.Explain(clause)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = fmt.Fprintf(m.dryRun, "%s;\n\n", query); err != nil {
+			return nil, err
+		}
+	}
+	return migrations, nil
+}
+
+type record struct {
+	Version   int64     `sql:"version"`
+	Name      string    `sql:"name"`
+	Checksum  string    `sql:"checksum"`
+	AppliedAt time.Time `sql:"applied_at"`
+}
+
+func (m *Migrator) createTable() *dbs.CreateTableBuilder {
+	var cb = dbs.NewCreateTableBuilder()
+	cb.UseSession(m.db)
+	cb.Table(m.table)
+	cb.IfNotExists()
+	cb.Column(dbs.ColumnDef{Name: "version", Type: "BIGINT", NotNull: true})
+	cb.Column(dbs.ColumnDef{Name: "name", Type: "VARCHAR(255)", NotNull: true})
+	cb.Column(dbs.ColumnDef{Name: "checksum", Type: "VARCHAR(64)", NotNull: true})
+	cb.Column(dbs.ColumnDef{Name: "applied_at", GoType: reflect.TypeOf(time.Time{}), NotNull: true})
+	cb.PrimaryKey("version")
+	return cb
+}
+
+// history 读取历史表中的记录，withTime 为 false 时不读取执行时间，避免驱动无法扫描时间类型。
+func (m *Migrator) history(ctx context.Context, withTime bool) (map[int64]*record, error) {
+	var sb = dbs.NewSelectBuilder()
+	sb.UseSession(m.db)
+	sb.Selects("version", "name", "checksum")
+	if withTime {
+		sb.Selects("applied_at")
+	}
+	sb.From(m.table)
+
+	var records []*record
+	if err := sb.Scan(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	var history = make(map[int64]*record, len(records))
+	for _, record := range records {
+		history[record.Version] = record
+	}
+	return history, nil
+}
+
+// insertHistory 以及 deleteHistory 返回的 Builder 没有设置 Session，由调用方设置 Session 或者 Dialect。
+func (m *Migrator) insertHistory(migration *Migration) *dbs.InsertBuilder {
+	var ib = dbs.NewInsertBuilder()
+	ib.Table(m.table)
+	ib.Columns("version", "name", "checksum", "applied_at")
+	ib.Values(migration.Version, migration.Name, migration.Checksum, time.Now())
+	return ib
+}
+
+func (m *Migrator) deleteHistory(migration *Migration) *dbs.DeleteBuilder {
+	var db = dbs.NewDeleteBuilder()
+	db.Table(m.table)
+	db.Where("version = ?", migration.Version)
+	return db
+}
@@ -0,0 +1,325 @@
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/mysql"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/internal/dbstest"
+	"github.com/smartwalle/dbs/migrate"
+)
+
+// fakeStore 在内存中维护历史表以及锁表，用于在没有数据库的情况下测试 Migrator。
+type fakeStore struct {
+	*dbstest.Driver
+	mu      sync.Mutex
+	history map[int64][]driver.Value
+	locked  bool
+}
+
+// sqliteError 与 github.com/mattn/go-sqlite3 的 sqlite3.Error 相同，使用 ExtendedCode 字段表示扩展错误码。
+type sqliteError struct {
+	Code         int
+	ExtendedCode int
+}
+
+func (e sqliteError) Error() string {
+	return "UNIQUE constraint failed: schema_migrations_lock.id"
+}
+
+// exec 作为 dbstest.Driver 的 Fail 更新历史表以及锁表。
+func (f *fakeStore) exec(query string, args []driver.Value) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations_lock"):
+		if f.locked {
+			return sqliteError{Code: 19, ExtendedCode: 1555}
+		}
+		f.locked = true
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations_lock"):
+		f.locked = false
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		f.history[args[0].(int64)] = args[:3]
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		delete(f.history, args[0].(int64))
+	case strings.Contains(query, "FAIL"):
+		return errors.New("fake error")
+	case strings.HasPrefix(query, "SELECT"):
+		var result = dbstest.Result{Columns: []string{"version", "name", "checksum"}}
+		for _, row := range f.history {
+			result.Rows = append(result.Rows, row)
+		}
+		sort.Slice(result.Rows, func(i, j int) bool {
+			return result.Rows[i][0].(int64) < result.Rows[j][0].(int64)
+		})
+		f.SetResult("schema_migrations", result)
+	}
+	return nil
+}
+
+// statements 返回迁移执行的语句，不包括事务、查询以及历史表和锁表相关的语句。
+func (f *fakeStore) statements() []string {
+	var execs []string
+	for _, query := range f.Statements() {
+		switch {
+		case query == "BEGIN" || query == "COMMIT" || query == "ROLLBACK":
+		case strings.HasPrefix(query, "SELECT"):
+		case strings.Contains(query, "schema_migrations"):
+		default:
+			execs = append(execs, query)
+		}
+	}
+	return execs
+}
+
+func openFakeDB(t *testing.T) (*dbs.DB, *fakeStore) {
+	var fake = &fakeStore{Driver: dbstest.NewDriver(nil), history: make(map[int64][]driver.Value)}
+	fake.Fail = fake.exec
+	return dbstest.Open(t, fake.Driver), fake
+}
+
+var migrations = fstest.MapFS{
+	"migrations/0001_create_user.up.sql": {Data: []byte(`
+-- 用户表; 注释中的分号不会拆分语句
+CREATE TABLE user (id BIGINT, name VARCHAR(64) DEFAULT 'a;b');
+INSERT INTO user (id, name) VALUES (1, 'admin');
+`)},
+	"migrations/0001_create_user.down.sql": {Data: []byte("DROP TABLE user;")},
+	"migrations/0002_create_function.up.sql": {Data: []byte(`CREATE FUNCTION one() RETURNS integer AS $$ SELECT 1; $$ LANGUAGE SQL;
+/* 只有注释的语句会被丢弃; */`)},
+	"migrations/README.md": {Data: []byte("ignored")},
+}
+
+func newMigrator(t *testing.T, db *dbs.DB, opts ...migrate.Option) *migrate.Migrator {
+	var m = migrate.New(db, opts...)
+	if err := m.AddFS(migrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	var up = func(ctx context.Context, tx *dbs.Tx) error {
+		if dbs.TxFromContext(ctx) != tx {
+			t.Fatal("ctx 应该绑定迁移使用的事务")
+		}
+		_, err := dbs.Exec(ctx, tx, "UPDATE user SET name = 'root'")
+		return err
+	}
+	var down = func(ctx context.Context, tx *dbs.Tx) error {
+		_, err := dbs.Exec(ctx, tx, "UPDATE user SET name = 'admin'")
+		return err
+	}
+	if err := m.Add(3, "rename_admin", up, down); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMigrator_AddFS(t *testing.T) {
+	var db, _ = openFakeDB(t)
+	var m = newMigrator(t, db)
+
+	var list = m.Migrations()
+	if len(list) != 3 {
+		t.Fatalf("期望迁移数量: %d, 实际迁移数量: %d", 3, len(list))
+	}
+	if list[0].Name != "create_user" || list[0].Checksum == "" || !list[0].Reversible() {
+		t.Fatalf("SQL 文件迁移解析错误: %+v", list[0])
+	}
+	if list[1].Reversible() {
+		t.Fatal("没有 down 文件的迁移不能回滚")
+	}
+	if list[2].Checksum != "" {
+		t.Fatal("Go 函数迁移不应该有 checksum")
+	}
+
+	if err := m.Add(1, "duplicate", func(ctx context.Context, tx *dbs.Tx) error { return nil }, nil); !errors.Is(err, migrate.ErrDuplicateVersion) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", migrate.ErrDuplicateVersion, err)
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var m = newMigrator(t, db)
+	var ctx = context.Background()
+
+	var applied, err = m.UpTo(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("期望执行数量: %d, 实际执行数量: %d", 2, len(applied))
+	}
+
+	var expects = []string{
+		"-- 用户表; 注释中的分号不会拆分语句\nCREATE TABLE user (id BIGINT, name VARCHAR(64) DEFAULT 'a;b')",
+		"INSERT INTO user (id, name) VALUES (1, 'admin')",
+		"CREATE FUNCTION one() RETURNS integer AS $$ SELECT 1; $$ LANGUAGE SQL",
+	}
+	if execs := fake.statements(); strings.Join(execs, "\n") != strings.Join(expects, "\n") {
+		t.Fatalf("期望执行: %q, 实际执行: %q", expects, execs)
+	}
+
+	if applied, err = m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0].Version != 3 {
+		t.Fatalf("期望执行版本: 3, 实际执行: %v", applied)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Missing || status.Modified {
+			t.Fatalf("迁移状态错误: %+v", status)
+		}
+	}
+
+	if applied, err = m.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0].Version != 3 {
+		t.Fatalf("期望回滚版本: 3, 实际回滚: %v", applied)
+	}
+	if _, err = m.DownTo(ctx, 0); !errors.Is(err, migrate.ErrIrreversible) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", migrate.ErrIrreversible, err)
+	}
+	if fake.locked {
+		t.Fatal("执行结束之后应该释放锁")
+	}
+}
+
+func TestMigrator_Failure(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var m = newMigrator(t, db)
+	m.Add(4, "fail", func(ctx context.Context, tx *dbs.Tx) error {
+		_, err := dbs.Exec(ctx, tx, "FAIL")
+		return err
+	}, nil)
+
+	var applied, err = m.Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "4 fail") {
+		t.Fatalf("期望迁移 4 执行失败, 实际错误: %v", err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("期望执行数量: %d, 实际执行数量: %d", 3, len(applied))
+	}
+	if _, ok := fake.history[4]; ok {
+		t.Fatal("执行失败的迁移不应该记录到历史表")
+	}
+}
+
+func TestMigrator_Checksum(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var m = newMigrator(t, db)
+	var ctx = context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	fake.history[1][2] = "modified"
+
+	if _, err := m.Up(ctx); !errors.Is(err, migrate.ErrChecksumMismatch) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", migrate.ErrChecksumMismatch, err)
+	}
+
+	var statuses, err = m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !statuses[0].Modified {
+		t.Fatal("SQL 文件的内容发生变化之后状态应该为 Modified")
+	}
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var m = newMigrator(t, db)
+	fake.locked = true
+
+	if _, err := m.Up(context.Background()); !errors.Is(err, migrate.ErrLocked) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", migrate.ErrLocked, err)
+	}
+	if len(fake.history) != 0 {
+		t.Fatal("没有获取到锁时不应该执行迁移")
+	}
+}
+
+func TestMigrator_LockError(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var m = newMigrator(t, db)
+	var errLock error
+	fake.Fail = func(query string, args []driver.Value) error {
+		if strings.HasPrefix(query, "INSERT INTO schema_migrations_lock") {
+			return errLock
+		}
+		return fake.exec(query, args)
+	}
+
+	// 只有错误码为违反唯一约束的错误表示已经被锁定，不根据错误信息判断
+	for _, errLock = range []error{
+		errors.New("connection refused"),
+		errors.New("duplicate key"),
+		sqliteError{Code: 19, ExtendedCode: 1299},
+	} {
+		if _, err := m.Up(context.Background()); !errors.Is(err, errLock) || errors.Is(err, migrate.ErrLocked) {
+			t.Fatalf("期望错误: %v, 实际错误: %v", errLock, err)
+		}
+	}
+
+	// 锁属于连接时至少需要 2 个连接，否则执行迁移时会一直等待
+	db.UseDialect(mysql.Dialect())
+	db.DB().SetMaxOpenConns(1)
+	if _, err := migrate.New(db).Up(context.Background()); !errors.Is(err, migrate.ErrLockConnection) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", migrate.ErrLockConnection, err)
+	}
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	var db, fake = openFakeDB(t)
+	var buf bytes.Buffer
+	var m = newMigrator(t, db, migrate.WithDryRun(&buf))
+
+	var applied, err = m.Up(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 {
+		t.Fatalf("期望执行数量: %d, 实际执行数量: %d", 3, len(applied))
+	}
+	if len(fake.statements()) != 0 || len(fake.history) != 0 || fake.locked {
+		t.Fatal("dry run 不应该执行任何语句")
+	}
+
+	var output = buf.String()
+	for _, expect := range []string{
+		"-- 1 create_user (up)\n",
+		"INSERT INTO user (id, name) VALUES (1, 'admin');\n",
+		"INSERT INTO schema_migrations (version,name,checksum,applied_at) VALUES (1,'create_user','" + applied[0].Checksum + "',",
+		"-- 3 rename_admin (up)\n-- go migration, SQL is not available in dry run\n",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Fatalf("期望输出包含: %q, 实际输出: %s", expect, output)
+		}
+	}
+
+	// 历史表的语句使用 db 的 Dialect 生成，$N 占位符同样需要代入参数
+	buf.Reset()
+	db.UseDialect(postgres.Dialect())
+	if _, err = m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if output = buf.String(); !strings.Contains(output, "VALUES (1,'create_user','") || strings.Contains(output, "$1") {
+		t.Fatalf("期望输出代入参数, 实际输出: %s", output)
+	}
+}
@@ -0,0 +1,178 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/smartwalle/dbs"
+)
+
+// kFilePattern 匹配 0001_create_user.up.sql 以及 0001_create_user.down.sql 这样的文件名
+var kFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// AddFS 添加 fsys 中 dir 目录下的 SQL 文件迁移，一般为 embed.FS，也可以使用 os.DirFS()。
+//
+// 文件名为 <版本号>_<名称>.up.sql 以及 <版本号>_<名称>.down.sql，down 文件可以省略，其它文件会被忽略。
+// 文件中的多条语句使用分号分隔，会依次执行，字符串、注释以及 PostgreSQL 的 $$ 中的分号不会作为分隔符。
+func (m *Migrator) AddFS(fsys fs.FS, dir string) error {
+	var entries, err = fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	var migrations = make(map[int64]*Migration)
+	var versions []int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var matches = kFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("migrate: invalid version in %s: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		var migration = migrations[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: matches[2], fromSQL: true}
+			migrations[version] = migration
+			versions = append(versions, version)
+		} else if migration.Name != matches[2] {
+			return fmt.Errorf("%w: %d (%s, %s)", ErrDuplicateVersion, version, migration.Name, matches[2])
+		}
+
+		var statements = splitStatements(string(data))
+		if matches[3] == "up" {
+			var sum = sha256.Sum256(data)
+			migration.Checksum = hex.EncodeToString(sum[:])
+			migration.upSQL = statements
+			migration.up = execStatements(statements)
+		} else {
+			migration.downSQL = statements
+			migration.down = execStatements(statements)
+		}
+	}
+
+	for _, version := range versions {
+		var migration = migrations[version]
+		if migration.up == nil {
+			return fmt.Errorf("migrate: migration %d %s has no up file", migration.Version, migration.Name)
+		}
+		if err = m.add(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execStatements(statements []string) Func {
+	return func(ctx context.Context, tx *dbs.Tx) error {
+		for _, statement := range statements {
+			if _, err := dbs.Exec(ctx, tx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitStatements 使用分号拆分 SQL 语句，忽略字符串、带引号的标识符、注释以及 $tag$ 中的分号，只包含注释的语句会被丢弃。
+func splitStatements(src string) []string {
+	var statements []string
+	var start = 0
+	var empty = true
+	for idx := 0; idx < len(src); {
+		var c = src[idx]
+		switch {
+		case c == '-' && strings.HasPrefix(src[idx:], "--"):
+			idx = skipUntil(src, idx, "\n")
+			continue
+		case c == '/' && strings.HasPrefix(src[idx:], "/*"):
+			idx = skipUntil(src, idx+2, "*/")
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			idx = skipQuoted(src, idx)
+			empty = false
+			continue
+		case c == '$':
+			if tag := dollarTag(src[idx:]); tag != "" {
+				idx = skipUntil(src, idx+len(tag), tag)
+				empty = false
+				continue
+			}
+		case c == ';':
+			if !empty {
+				statements = append(statements, strings.TrimSpace(src[start:idx]))
+			}
+			start = idx + 1
+			empty = true
+			idx++
+			continue
+		}
+
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			empty = false
+		}
+		idx++
+	}
+	if !empty {
+		statements = append(statements, strings.TrimSpace(src[start:]))
+	}
+	return statements
+}
+
+// skipUntil 返回 src[from:] 中第一个 end 之后的位置，没有找到时返回 len(src)。
+func skipUntil(src string, from int, end string) int {
+	var pos = strings.Index(src[from:], end)
+	if pos < 0 {
+		return len(src)
+	}
+	return from + pos + len(end)
+}
+
+// skipQuoted 返回 src[from] 开始的引号对应的结束引号之后的位置，字符串中的 \ 会转义下一个字符。
+func skipQuoted(src string, from int) int {
+	var quote = src[from]
+	for idx := from + 1; idx < len(src); idx++ {
+		switch src[idx] {
+		case '\\':
+			if quote != '`' {
+				idx++
+			}
+		case quote:
+			return idx + 1
+		}
+	}
+	return len(src)
+}
+
+// dollarTag 返回 PostgreSQL 的 $tag$ 或者 $$，s 不以其开头时返回空字符串，$1 这样的占位符不会被识别。
+func dollarTag(s string) string {
+	for idx := 1; idx < len(s); idx++ {
+		var c = s[idx]
+		switch {
+		case c == '$':
+			return s[:idx+1]
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && idx > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package dbs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy 用于将未设置标签的结构体字段名转换为列名。
+type NamingStrategy func(name string) string
+
+// SnakeCase 将字段名转换为下划线命名，如 UserID 转换为 user_id，CreatedAt 转换为 created_at。
+func SnakeCase(name string) string {
+	var runes = []rune(name)
+	var builder strings.Builder
+	builder.Grow(len(name) + 4)
+	for idx, r := range runes {
+		if unicode.IsUpper(r) {
+			if idx > 0 && runes[idx-1] != '_' {
+				var prevLower = unicode.IsLower(runes[idx-1]) || unicode.IsDigit(runes[idx-1])
+				var nextLower = idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+				if prevLower || (nextLower && unicode.IsUpper(runes[idx-1])) {
+					builder.WriteByte('_')
+				}
+			}
+			builder.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// LowerCase 将字段名转换为小写，如 UserID 转换为 userid。
+func LowerCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// Identity 直接使用字段名作为列名。
+func Identity(name string) string {
+	return name
+}
+
+type MapperOption func(m *mapper)
+
+// WithNamingStrategy 为未设置标签的导出字段生成列名，未设置时忽略这些字段（结构体字段除外）。
+//
+// 未设置标签的 time.Time 以及实现了 sql.Scanner 或者 driver.Valuer 接口的结构体字段会被当作普通列处理，其它结构体字段仍然会被展开。
+func WithNamingStrategy(naming NamingStrategy) MapperOption {
+	return func(m *mapper) {
+		m.naming = naming
+	}
+}
+
+// WithCaseInsensitive 查找列对应的字段时忽略大小写，精确匹配优先。
+func WithCaseInsensitive() MapperOption {
+	return func(m *mapper) {
+		m.caseInsensitive = true
+	}
+}
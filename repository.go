@@ -3,6 +3,9 @@ package dbs
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
 )
 
 const (
@@ -10,6 +13,8 @@ const (
 	kDefaultBatchSize = 100
 )
 
+var ErrPrimaryKeyMismatch = errors.New("dbs: primary key values count does not match primary key columns")
+
 type Entity interface {
 	TableName() string
 
@@ -45,12 +50,18 @@ type Repository[E Entity] interface {
 
 	FindOrderedList(ctx context.Context, columns, orderBy, conds string, args ...any) ([]*E, error)
 
+	// Transaction 在事务中执行 fn，fn 返回错误时回滚事务。
+	//
+	// ctx 中已经存在事务时（嵌套调用）在保存点中执行 fn，fn 返回错误时只回滚到该保存点，由外层的 fn 决定是否继续。
 	Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error
 }
 
 type repository[E Entity] struct {
 	entity E
 	db     Database
+
+	keysOnce sync.Once
+	keys     []string
 }
 
 func NewRepository[E Entity](db Database) Repository[E] {
@@ -104,7 +115,7 @@ func (r *repository[E]) Create(ctx context.Context, entity *E) (sql.Result, erro
 	var values = make([]any, 0, len(fieldValues))
 
 	for _, fieldValue := range fieldValues {
-		if fieldValue.UseDefault {
+		if fieldValue.UseDefault || fieldValue.OmitEmpty || !fieldValue.Insertable() {
 			continue
 		}
 		columns = append(columns, fieldValue.Name)
@@ -125,48 +136,74 @@ func (r *repository[E]) CreateInBatches(ctx context.Context, batchSize int, enti
 		batchSize = kDefaultBatchSize
 	}
 
-	var columns []string
-	var rows = make([][]any, 0, len(entities))
+	// 多行数据需要保持相同的列，Dialect 支持 DEFAULT 关键字时忽略的零值使用 DEFAULT，否则按照省略的列分组
+	var useDefault = dialectSupportsDefault(r.db.Dialect())
+	var groups []*insertGroup
+	var groupIndex = make(map[string]*insertGroup)
 
-	for idx, entity := range entities {
+	for _, entity := range entities {
 		var fieldValues, err = r.db.Mapper().Encode(entity)
 		if err != nil {
 			return nil, err
 		}
 
-		if idx == 0 {
-			columns = make([]string, len(fieldValues))
+		var columns = make([]string, 0, len(fieldValues))
+		var values = make([]any, 0, len(fieldValues))
+		var omitted = make([]byte, 0, len(fieldValues))
+		for _, fieldValue := range fieldValues {
+			if !fieldValue.Insertable() {
+				continue
+			}
+			var value = fieldValue.Value
+			if fieldValue.UseDefault || fieldValue.OmitEmpty {
+				if !useDefault {
+					omitted = append(omitted, '1')
+					continue
+				}
+				value = SQL("DEFAULT")
+			}
+			omitted = append(omitted, '0')
+			columns = append(columns, fieldValue.Name)
+			values = append(values, value)
 		}
 
-		var values = make([]any, len(fieldValues))
-		for i, fieldValue := range fieldValues {
-			if idx == 0 {
-				columns[i] = fieldValue.Name
-			}
-			values[i] = fieldValue.Value
+		var group = groupIndex[string(omitted)]
+		if group == nil {
+			group = &insertGroup{columns: columns}
+			groupIndex[string(omitted)] = group
+			groups = append(groups, group)
 		}
-		rows = append(rows, values)
+		group.rows = append(group.rows, values)
 	}
 
 	var results insertResults
 	var err = r.Transaction(ctx, func(ctx context.Context) error {
-		for start := 0; start < len(rows); start += batchSize {
-			var end = start + batchSize
-			if end > len(rows) {
-				end = len(rows)
-			}
-
-			var ib = r.InsertBuilder(ctx)
-			ib.Columns(columns...)
-			for _, values := range rows[start:end] {
-				ib.Values(values...)
+		for _, group := range groups {
+			for start := 0; start < len(group.rows); start += batchSize {
+				var end = start + batchSize
+				if end > len(group.rows) {
+					end = len(group.rows)
+				}
+
+				var ib = r.InsertBuilder(ctx)
+				ib.Columns(group.columns...)
+				for _, values := range group.rows[start:end] {
+					ib.Values(values...)
+				}
+
+				// 按照 Dialect 声明的参数数量和字节数限制进一步拆分
+				var builders, err = ib.Split()
+				if err != nil {
+					return err
+				}
+				for _, builder := range builders {
+					var result, err = builder.Exec(withDepth(ctx, kRepositoryDepth+2))
+					if err != nil {
+						return err
+					}
+					results = append(results, result)
+				}
 			}
-
-			var result, err = ib.Exec(withDepth(ctx, kRepositoryDepth+2))
-			if err != nil {
-				return err
-			}
-			results = append(results, result)
 		}
 		return nil
 	})
@@ -176,24 +213,64 @@ func (r *repository[E]) CreateInBatches(ctx context.Context, batchSize int, enti
 	return results, nil
 }
 
+// insertGroup 为 CreateInBatches 中插入相同列的多行数据。
+type insertGroup struct {
+	columns []string
+	rows    [][]any
+}
+
 func (r *repository[E]) Delete(ctx context.Context, id any) (sql.Result, error) {
 	var rb = r.DeleteBuilder(ctx)
-	rb.Where(r.entity.PrimaryKey()+" = ?", id)
+	rb.Where(r.primaryKey(id))
 	return rb.Exec(withDepth(ctx, kRepositoryDepth))
 }
 
 func (r *repository[E]) Update(ctx context.Context, id any, values map[string]any) (sql.Result, error) {
 	var ub = r.UpdateBuilder(ctx)
 	ub.SetValues(values)
-	ub.Where(r.entity.PrimaryKey()+" = ?", id)
+	ub.Where(r.primaryKey(id))
 	return ub.Exec(withDepth(ctx, kRepositoryDepth))
 }
 
+// UpdateEntity 使用实体的字段更新 repo 中主键对应的记录，主键（pk 标签或者 Entity.PrimaryKey()）、readonly、insert 字段以及被忽略的零值不会被更新。
+func UpdateEntity[E Entity](ctx context.Context, repo Repository[E], entity *E) (sql.Result, error) {
+	var r, ok = repo.(*repository[E])
+	if !ok {
+		// 其它实现（如嵌入了 Repository 的结构体）使用相同的 Database 以及表名
+		r = &repository[E]{db: repo.Database()}
+	}
+
+	var fieldValues, err = r.db.Mapper().Encode(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys = r.primaryKeys()
+	var ub = repo.UpdateBuilder(ctx)
+	for _, fieldValue := range fieldValues {
+		if fieldValue.UseDefault || fieldValue.OmitEmpty || !fieldValue.Updatable() || containsString(keys, fieldValue.Name) {
+			continue
+		}
+		ub.Set(fieldValue.Name, fieldValue.Value)
+	}
+	ub.Where(r.primaryKey(entity))
+	return ub.Exec(withDepth(ctx, kRepositoryDepth))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *repository[E]) Find(ctx context.Context, id any, columns string) (entity *E, err error) {
 	var sb = r.SelectBuilder(ctx)
-	sb.Selects(columns)
+	r.selects(sb, columns)
 	sb.Limit(1)
-	sb.Where(r.entity.PrimaryKey()+" = ?", id)
+	sb.Where(r.primaryKey(id))
 
 	if err = sb.Scan(withDepth(ctx, kRepositoryDepth), &entity); err != nil {
 		return nil, err
@@ -203,7 +280,7 @@ func (r *repository[E]) Find(ctx context.Context, id any, columns string) (entit
 
 func (r *repository[E]) FindOne(ctx context.Context, columns string, conds string, args ...any) (entity *E, err error) {
 	var sb = r.SelectBuilder(ctx)
-	sb.Selects(columns)
+	r.selects(sb, columns)
 	sb.Limit(1)
 	sb.Where(conds, args...)
 
@@ -215,7 +292,7 @@ func (r *repository[E]) FindOne(ctx context.Context, columns string, conds strin
 
 func (r *repository[E]) FindList(ctx context.Context, columns, conds string, args ...any) (entityList []*E, err error) {
 	var sb = r.SelectBuilder(ctx)
-	sb.Selects(columns)
+	r.selects(sb, columns)
 	sb.Where(conds, args...)
 
 	if err = sb.Scan(withDepth(ctx, kRepositoryDepth), &entityList); err != nil {
@@ -226,7 +303,7 @@ func (r *repository[E]) FindList(ctx context.Context, columns, conds string, arg
 
 func (r *repository[E]) FindOrderedList(ctx context.Context, columns, orderBy, conds string, args ...any) (entityList []*E, err error) {
 	var sb = r.SelectBuilder(ctx)
-	sb.Selects(columns)
+	r.selects(sb, columns)
 	sb.OrderBy(orderBy)
 	sb.Where(conds, args...)
 
@@ -236,30 +313,92 @@ func (r *repository[E]) FindOrderedList(ctx context.Context, columns, orderBy, c
 	return entityList, nil
 }
 
-func (r *repository[E]) Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) (err error) {
-	var tx = TxFromContext(ctx)
-	if tx == nil {
-		var opt *sql.TxOptions
-		if len(opts) > 0 {
-			opt = opts[0]
+// primaryKeys 返回实体的主键列，优先使用 pk 标签，没有设置 pk 标签时使用 Entity.PrimaryKey()，只会在第一次调用时解析。
+func (r *repository[E]) primaryKeys() []string {
+	r.keysOnce.Do(func() {
+		if fieldValues, err := r.db.Mapper().Encode(r.entity); err == nil {
+			for _, fieldValue := range fieldValues {
+				if fieldValue.PrimaryKey {
+					r.keys = append(r.keys, fieldValue.Name)
+				}
+			}
 		}
-		tx, err = r.db.BeginTx(ctx, opt)
-		if err != nil {
-			return err
+		if len(r.keys) == 0 {
+			r.keys = []string{r.entity.PrimaryKey()}
+		}
+	})
+	return r.keys
+}
+
+// primaryKey 生成主键条件。
+//
+// 单一主键时 id 为主键的值；复合主键时 id 可以为 E、*E，或者按照主键顺序排列的切片（数组）。
+func (r *repository[E]) primaryKey(id any) SQLClause {
+	var keys = r.primaryKeys()
+
+	var values []any
+	switch raw := id.(type) {
+	case E:
+		values = r.primaryKeyValues(keys, raw)
+	case *E:
+		values = r.primaryKeyValues(keys, raw)
+	default:
+		if len(keys) == 1 {
+			return SQL(keys[0]+" = ?", id)
 		}
-		defer func() {
-			if err != nil {
-				_ = tx.Rollback()
+		var value = reflect.ValueOf(id)
+		if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+			values = make([]any, value.Len())
+			for idx := range values {
+				values[idx] = value.Index(idx).Interface()
 			}
-		}()
+		}
+	}
+
+	if len(values) != len(keys) {
+		return errorClause{err: ErrPrimaryKeyMismatch}
+	}
 
-		if err = fn(tx.WithContext(ctx)); err != nil {
-			return err
+	var conds = AND()
+	conds.ignoreBracket = true
+	for idx, key := range keys {
+		conds.Append(key+" = ?", values[idx])
+	}
+	return conds
+}
+
+func (r *repository[E]) primaryKeyValues(keys []string, entity any) []any {
+	var fieldValues, err = r.db.Mapper().Encode(entity)
+	if err != nil {
+		return nil
+	}
+	var values = make([]any, 0, len(keys))
+	for _, key := range keys {
+		for _, fieldValue := range fieldValues {
+			if fieldValue.Name == key {
+				values = append(values, fieldValue.Value)
+				break
+			}
 		}
-		return tx.Commit()
 	}
+	return values
+}
 
-	return fn(ctx)
+// selects 设置查询的列，columns 为空时查询实体映射的所有列。
+func (r *repository[E]) selects(sb *SelectBuilder, columns string) {
+	if columns == "" {
+		sb.SelectsOf(r.entity)
+		return
+	}
+	sb.Selects(columns)
+}
+
+func (r *repository[E]) Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	var opt *sql.TxOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return transaction(ctx, r.db, fn, opt)
 }
 
 type insertResults []sql.Result
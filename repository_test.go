@@ -0,0 +1,105 @@
+package dbs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+type repoUser struct {
+	Id        int64  `sql:"id"`
+	Name      string `sql:"name"`
+	Email     string `sql:"email;insert"`
+	CreatedAt int64  `sql:"created_at;readonly"`
+}
+
+func (repoUser) TableName() string {
+	return "user"
+}
+
+func (repoUser) PrimaryKey() string {
+	return "id"
+}
+
+type repoUserRole struct {
+	UserId int64  `sql:"user_id;pk"`
+	RoleId int64  `sql:"role_id;pk"`
+	Note   string `sql:"note"`
+}
+
+func (repoUserRole) TableName() string {
+	return "user_role"
+}
+
+func (repoUserRole) PrimaryKey() string {
+	return ""
+}
+
+// repoUserRepository 与 dbsgen 生成的 Repository 一样嵌入 dbs.Repository。
+type repoUserRepository struct {
+	dbs.Repository[repoUser]
+}
+
+func TestUpdateEntity(t *testing.T) {
+	var db, d = openTxDB(t)
+	var ctx = context.Background()
+
+	// 通过 PrimaryKey() 指定的主键不会出现在 SET 中
+	var repo = dbs.NewRepository[repoUser](db)
+	if _, err := dbs.UpdateEntity(ctx, repo, &repoUser{Id: 1, Name: "a", Email: "b", CreatedAt: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbs.UpdateEntity[repoUser](ctx, &repoUserRepository{Repository: repo}, &repoUser{Id: 2, Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbs.UpdateEntity(ctx, dbs.NewRepository[repoUserRole](db), &repoUserRole{UserId: 1, RoleId: 2, Note: "c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = "UPDATE user SET name=? WHERE id = ?; UPDATE user SET name=? WHERE id = ?; UPDATE user_role SET note=? WHERE user_id = ? AND role_id = ?"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+}
+
+type repoProfile struct {
+	Id       int64  `sql:"id;default"`
+	Nickname string `sql:"nickname;omitempty"`
+	Age      int    `sql:"age"`
+}
+
+func (repoProfile) TableName() string {
+	return "profile"
+}
+
+func (repoProfile) PrimaryKey() string {
+	return "id"
+}
+
+func TestRepository_CreateInBatches(t *testing.T) {
+	var db, d = openTxDB(t)
+	var ctx = context.Background()
+	var repo = dbs.NewRepository[repoProfile](db)
+	var entities = []*repoProfile{{Nickname: "a", Age: 1}, {Age: 2}, {Nickname: "c", Age: 3}}
+
+	if _, err := repo.CreateInBatches(ctx, 10, entities...); err != nil {
+		t.Fatal(err)
+	}
+	var expect = "BEGIN; INSERT INTO profile (id,nickname,age) VALUES (DEFAULT,?,?),(DEFAULT,DEFAULT,?),(DEFAULT,?,?); COMMIT"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+
+	// SQLite 不支持 DEFAULT 关键字，按照省略的列分组插入
+	d.Reset()
+	db.UseDialect(sqlite.Dialect())
+	if _, err := repo.CreateInBatches(ctx, 10, entities...); err != nil {
+		t.Fatal(err)
+	}
+	expect = "BEGIN; INSERT INTO profile (nickname,age) VALUES (?,?),(?,?); INSERT INTO profile (age) VALUES (?); COMMIT"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+}
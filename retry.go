@@ -0,0 +1,133 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+)
+
+// RetryClassifier 是 Dialect 的可选接口，用于判断事务失败的错误是否可以通过重新执行事务解决，如序列化冲突、死锁。
+//
+// mysql.Dialect() 重试 1213（死锁），postgres.Dialect() 重试 40001（序列化冲突）以及 40P01（死锁）。
+type RetryClassifier interface {
+	Retryable(err error) bool
+}
+
+// Retryable 使用 dialect 判断 err 是否可以重试，dialect 没有实现 RetryClassifier 接口时返回 false。
+func Retryable(dialect Dialect, err error) bool {
+	if classifier, ok := dialect.(RetryClassifier); ok && err != nil {
+		return classifier.Retryable(err)
+	}
+	return false
+}
+
+type retryOptions struct {
+	attempts   int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	classifier func(err error) bool
+	txOptions  *sql.TxOptions
+}
+
+type RetryOption func(opts *retryOptions)
+
+// WithAttempts 设置最多执行事务的次数（包括第一次），默认为 3。
+func WithAttempts(attempts int) RetryOption {
+	return func(opts *retryOptions) {
+		if attempts > 0 {
+			opts.attempts = attempts
+		}
+	}
+}
+
+// WithBackoff 设置重试的等待时间，第 n 次重试在 [0, min(max, min * 2^(n-1))) 中随机等待，默认为 10ms 和 1s。
+func WithBackoff(min, max time.Duration) RetryOption {
+	return func(opts *retryOptions) {
+		if min > 0 {
+			opts.minBackoff = min
+		}
+		if max >= min {
+			opts.maxBackoff = max
+		}
+	}
+}
+
+// WithClassifier 替换 Dialect 提供的 RetryClassifier，返回 true 时重新执行事务。
+func WithClassifier(classifier func(err error) bool) RetryOption {
+	return func(opts *retryOptions) {
+		opts.classifier = classifier
+	}
+}
+
+// WithTxOptions 设置开始事务时使用的选项，如隔离级别。
+func WithTxOptions(txOptions *sql.TxOptions) RetryOption {
+	return func(opts *retryOptions) {
+		opts.txOptions = txOptions
+	}
+}
+
+// RetryTransaction 在事务中执行 fn，事务因为可以重试的错误失败时回滚并重新执行 fn，直到成功、错误不能重试或者达到最大次数，返回最后一次执行的错误。
+//
+//	err := dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+//		// fn 可能会被执行多次，不要在 fn 中修改外部的状态
+//		return nil
+//	}, dbs.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable}), dbs.WithAttempts(5))
+//
+// 默认使用 db.Dialect() 实现的 RetryClassifier 判断错误是否可以重试。
+// ctx 中已经存在事务时（嵌套调用）只会在保存点中执行 fn，不会重试，由最外层的事务负责重试。
+func RetryTransaction(ctx context.Context, db Database, fn func(ctx context.Context) error, opts ...RetryOption) (err error) {
+	var nOpts = &retryOptions{
+		attempts:   3,
+		minBackoff: 10 * time.Millisecond,
+		maxBackoff: time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(nOpts)
+		}
+	}
+
+	if TxFromContext(ctx) != nil {
+		return transaction(ctx, db, fn, nOpts.txOptions)
+	}
+
+	var classifier = nOpts.classifier
+	if classifier == nil {
+		classifier = func(err error) bool {
+			return Retryable(db.Dialect(), err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err = transaction(ctx, db, fn, nOpts.txOptions); err == nil {
+			return nil
+		}
+		if attempt >= nOpts.attempts || ctx.Err() != nil || !classifier(err) {
+			return err
+		}
+
+		var timer = time.NewTimer(backoff(nOpts.minBackoff, nOpts.maxBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 返回第 attempt 次重试之前需要等待的时间，使用指数退避以及完全随机的抖动。
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	var ceiling = min
+	for idx := 1; idx < attempt && ceiling < max; idx++ {
+		ceiling *= 2
+	}
+	if ceiling > max {
+		ceiling = max
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
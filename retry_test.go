@@ -0,0 +1,154 @@
+package dbs_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/mysql"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+)
+
+// pgError 与 github.com/lib/pq 的 *Error 一样通过 SQLState() 返回错误码。
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string {
+	return "pq: " + e.code
+}
+
+func (e *pgError) SQLState() string {
+	return e.code
+}
+
+// mysqlError 与 github.com/go-sql-driver/mysql 的 *MySQLError 有相同的 Number 字段。
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+func TestRetryable(t *testing.T) {
+	var tests = []struct {
+		dialect dbs.Dialect
+		err     error
+		expect  bool
+	}{
+		{postgres.Dialect(), &pgError{code: "40001"}, true},
+		{postgres.Dialect(), fmt.Errorf("update: %w", &pgError{code: "40P01"}), true},
+		{postgres.Dialect(), &pgError{code: "23505"}, false},
+		{postgres.Dialect(), errors.New("40001"), false},
+		{mysql.Dialect(), &mysqlError{Number: 1213}, true},
+		{mysql.Dialect(), fmt.Errorf("update: %w", &mysqlError{Number: 1062}), false},
+		{mysql.Dialect(), errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{sqlite.Dialect(), &pgError{code: "40001"}, false},
+		{nil, &mysqlError{Number: 1213}, false},
+	}
+	for _, test := range tests {
+		if actual := dbs.Retryable(test.dialect, test.err); actual != test.expect {
+			t.Fatalf("%s: 期望 %v, 实际 %v", test.err, test.expect, actual)
+		}
+	}
+}
+
+func TestRetryTransaction(t *testing.T) {
+	var db, d = openTxDB(t)
+	db.UseDialect(postgres.Dialect())
+	var ctx = context.Background()
+
+	var failures = 2
+	d.Fail = func(query string, args []driver.Value) error {
+		if query == "UPDATE" && failures > 0 {
+			failures--
+			return &pgError{code: "40001"}
+		}
+		return nil
+	}
+
+	var calls int
+	var err = dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+		calls++
+		return execInTx(ctx, "UPDATE")
+	}, dbs.WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("期望执行次数: %d, 实际执行次数: %d", 3, calls)
+	}
+	var expect = "BEGIN; ROLLBACK; BEGIN; ROLLBACK; BEGIN; UPDATE; COMMIT"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+
+	// 达到最大次数之后返回最后一次的错误
+	failures, calls = 5, 0
+	err = dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+		calls++
+		return execInTx(ctx, "UPDATE")
+	}, dbs.WithAttempts(2), dbs.WithBackoff(time.Millisecond, time.Millisecond))
+	if !dbs.Retryable(db.Dialect(), err) || calls != 2 {
+		t.Fatalf("期望执行 2 次并返回可以重试的错误, 实际执行 %d 次, 错误: %v", calls, err)
+	}
+
+	// 不能重试的错误直接返回
+	var errBusiness = errors.New("business")
+	calls = 0
+	err = dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+		calls++
+		return errBusiness
+	})
+	if !errors.Is(err, errBusiness) || calls != 1 {
+		t.Fatalf("期望执行 1 次并返回 %v, 实际执行 %d 次, 错误: %v", errBusiness, calls, err)
+	}
+
+	// 自定义的 classifier
+	calls = 0
+	err = dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+		calls++
+		return errBusiness
+	}, dbs.WithClassifier(func(err error) bool {
+		return errors.Is(err, errBusiness)
+	}), dbs.WithBackoff(time.Millisecond, time.Millisecond))
+	if calls != 3 {
+		t.Fatalf("期望执行次数: %d, 实际执行次数: %d", 3, calls)
+	}
+}
+
+func TestRetryTransaction_Nested(t *testing.T) {
+	var db, d = openTxDB(t)
+	db.UseDialect(postgres.Dialect())
+	var ctx = context.Background()
+
+	var outer, inner int
+	var err = dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+		outer++
+		// 嵌套的事务只使用保存点，由最外层的事务负责重试
+		return dbs.RetryTransaction(ctx, db, func(ctx context.Context) error {
+			inner++
+			if outer == 1 {
+				return &pgError{code: "40001"}
+			}
+			return execInTx(ctx, "UPDATE")
+		})
+	}, dbs.WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outer != 2 || inner != 2 {
+		t.Fatalf("期望外层以及内层各执行 2 次, 实际外层 %d 次, 内层 %d 次", outer, inner)
+	}
+	if actual := statements(d); strings.Count(actual, "BEGIN") != 2 || !strings.HasSuffix(actual, "UPDATE; RELEASE SAVEPOINT dbs_savepoint_1; COMMIT") {
+		t.Fatalf("执行的语句错误: %s", actual)
+	}
+}
@@ -65,6 +65,20 @@ func (db *DB) Mapper() dbs.Mapper {
 	return db.master.Mapper()
 }
 
+func (db *DB) SliceStrategy() dbs.SliceStrategy {
+	if raw, ok := db.master.(interface{ SliceStrategy() dbs.SliceStrategy }); ok {
+		return raw.SliceStrategy()
+	}
+	return dbs.SliceDefault
+}
+
+func (db *DB) SliceChunkSize() int {
+	if raw, ok := db.master.(interface{ SliceChunkSize() int }); ok {
+		return raw.SliceChunkSize()
+	}
+	return 0
+}
+
 func (db *DB) Session(ctx context.Context) dbs.Session {
 	var session, ok = ctx.Value(internal.TxSessionKey{}).(dbs.Session)
 	if ok && session != nil {
@@ -0,0 +1,263 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/smartwalle/dbs"
+)
+
+var ErrSchemaDrift = errors.New("schema: entity does not match table")
+
+// ProblemKind 为实体与表结构不一致的类型。
+type ProblemKind uint8
+
+const (
+	// MissingTable 实体对应的表不存在。
+	MissingTable ProblemKind = iota + 1
+
+	// MissingColumn 实体映射的列在表中不存在。
+	MissingColumn
+
+	// RequiredColumn 表中存在没有映射的列，该列不能为 NULL 并且没有默认值，插入数据时会失败。
+	RequiredColumn
+
+	// IncompatibleType 字段的类型无法与列的类型相互转换。
+	IncompatibleType
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case MissingTable:
+		return "table not found"
+	case MissingColumn:
+		return "column not found"
+	case RequiredColumn:
+		return "unmapped NOT NULL column without default"
+	case IncompatibleType:
+		return "incompatible type"
+	}
+	return "unknown problem"
+}
+
+// Problem 描述实体与表结构之间的一处不一致。
+type Problem struct {
+	Kind   ProblemKind
+	Entity reflect.Type
+	Table  string
+	Column string // Kind 为 MissingTable 时为空
+
+	FieldType  reflect.Type // Kind 为 IncompatibleType 时有效
+	ColumnType string       // Kind 为 RequiredColumn 或者 IncompatibleType 时有效
+}
+
+func (p Problem) String() string {
+	switch p.Kind {
+	case MissingTable:
+		return fmt.Sprintf("%s: %s (%s)", p.Table, p.Kind, p.Entity)
+	case IncompatibleType:
+		return fmt.Sprintf("%s.%s: %s, field %s, column %s", p.Table, p.Column, p.Kind, p.FieldType, p.ColumnType)
+	case RequiredColumn:
+		return fmt.Sprintf("%s.%s: %s (%s)", p.Table, p.Column, p.Kind, p.ColumnType)
+	}
+	return fmt.Sprintf("%s.%s: %s (%s)", p.Table, p.Column, p.Kind, p.Entity)
+}
+
+// DriftError 包含 CheckSchema 发现的所有不一致，errors.Is(err, ErrSchemaDrift) 返回 true。
+type DriftError struct {
+	Problems []Problem
+}
+
+func (e *DriftError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(ErrSchemaDrift.Error())
+	for _, problem := range e.Problems {
+		builder.WriteString("\n\t")
+		builder.WriteString(problem.String())
+	}
+	return builder.String()
+}
+
+func (e *DriftError) Unwrap() error {
+	return ErrSchemaDrift
+}
+
+// CheckSchema 使用 session 的 Mapper 解析 entities 映射的列，并与数据库中的表结构进行比较，可以在启动时或者测试中使用：
+//
+//	if err := schema.CheckSchema(ctx, db, User{}, Order{}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// 存在不一致时返回 *DriftError，其它错误（如读取表结构失败）直接返回。
+// 类型检查只针对能够确定的情况，实现了 sql.Scanner 接口或者注册了转换函数的字段以及无法识别的列类型不会被检查。
+func CheckSchema(ctx context.Context, session dbs.Session, entities ...dbs.Entity) error {
+	var inspector, err = InspectorOf(session.Dialect())
+	if err != nil {
+		return err
+	}
+
+	var problems []Problem
+	for _, entity := range entities {
+		var entityType = reflect.TypeOf(entity)
+		var name = entity.TableName()
+
+		var fields, err = dbs.FieldsOf(session.Mapper(), entity)
+		if err != nil {
+			return err
+		}
+
+		table, err := inspector.Table(ctx, session, name)
+		if errors.Is(err, ErrTableNotFound) {
+			problems = append(problems, Problem{Kind: MissingTable, Entity: entityType, Table: name})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		problems = append(problems, checkTable(entityType, table, fields)...)
+	}
+
+	if len(problems) > 0 {
+		return &DriftError{Problems: problems}
+	}
+	return nil
+}
+
+func checkTable(entityType reflect.Type, table *Table, fields []dbs.Field) []Problem {
+	var problems []Problem
+	var mapped = make(map[string]bool, len(fields))
+	for _, field := range fields {
+		mapped[field.Column] = true
+
+		var column = table.Column(field.Column)
+		if column == nil {
+			problems = append(problems, Problem{Kind: MissingColumn, Entity: entityType, Table: table.Name, Column: field.Column})
+			continue
+		}
+		if !compatible(field, column) {
+			problems = append(problems, Problem{
+				Kind:       IncompatibleType,
+				Entity:     entityType,
+				Table:      table.Name,
+				Column:     column.Name,
+				FieldType:  field.Type,
+				ColumnType: column.Type,
+			})
+		}
+	}
+
+	for _, column := range table.Columns {
+		if !mapped[column.Name] && !column.Nullable && !column.HasDefault() {
+			problems = append(problems, Problem{Kind: RequiredColumn, Entity: entityType, Table: table.Name, Column: column.Name, ColumnType: column.Type})
+		}
+	}
+	return problems
+}
+
+// typeClass 为列类型的大致分类。
+type typeClass uint8
+
+const (
+	classUnknown typeClass = iota
+	classInteger
+	classFloat
+	classDecimal
+	classString
+	classJSON
+	classBool
+	classTime
+	classBytes
+)
+
+var typeClasses = map[string]typeClass{
+	"tinyint": classInteger, "smallint": classInteger, "mediumint": classInteger, "int": classInteger, "integer": classInteger,
+	"bigint": classInteger, "serial": classInteger, "bigserial": classInteger, "year": classInteger,
+
+	"float": classFloat, "real": classFloat, "double": classFloat, "double precision": classFloat,
+
+	"decimal": classDecimal, "numeric": classDecimal,
+
+	"char": classString, "varchar": classString, "tinytext": classString, "text": classString, "mediumtext": classString,
+	"longtext": classString, "clob": classString, "enum": classString, "set": classString, "uuid": classString, "citext": classString,
+
+	"json": classJSON, "jsonb": classJSON,
+
+	"bool": classBool, "boolean": classBool,
+
+	"date": classTime, "datetime": classTime, "timestamp": classTime, "timestamptz": classTime,
+
+	"binary": classBytes, "varbinary": classBytes, "tinyblob": classBytes, "blob": classBytes, "mediumblob": classBytes,
+	"longblob": classBytes, "bytea": classBytes,
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// compatible 判断字段与列的类型是否可以相互转换，无法确定时返回 true。
+func compatible(field dbs.Field, column *Column) bool {
+	switch field.Encoding {
+	case dbs.EncodingConverter:
+		return true
+	case dbs.EncodingArray:
+		return column.Array || typeClasses[column.DataType] == classString
+	}
+	if column.Array {
+		// 数组可以扫描为字符串，如 {a,b}
+		return field.Encoding == dbs.EncodingNone && baseType(field.Type).Kind() == reflect.String
+	}
+
+	var class = typeClasses[column.DataType]
+	if class == classUnknown {
+		return true
+	}
+
+	switch field.Encoding {
+	case dbs.EncodingJSON:
+		return class == classJSON || class == classString || class == classBytes
+	case dbs.EncodingSet:
+		return class == classString
+	}
+
+	var fieldType = baseType(field.Type)
+	if fieldType == timeType {
+		return class == classTime
+	}
+	if fieldType == bytesType {
+		return class == classBytes || class == classString || class == classJSON
+	}
+	if reflect.PointerTo(fieldType).Implements(scannerType) || fieldType.Implements(valuerType) {
+		return true
+	}
+
+	switch kind := fieldType.Kind(); {
+	case kind == reflect.String:
+		return true
+	case kind == reflect.Bool:
+		return class == classBool || class == classInteger
+	case kind >= reflect.Int && kind <= reflect.Uint64:
+		return class == classInteger || class == classDecimal
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return class == classFloat || class == classDecimal || class == classInteger
+	case kind == reflect.Interface:
+		return true
+	}
+	return false
+}
+
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
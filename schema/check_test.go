@@ -0,0 +1,87 @@
+package schema_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs/dialect/sqlite"
+	"github.com/smartwalle/dbs/internal/dbstest"
+	"github.com/smartwalle/dbs/schema"
+)
+
+type checkUser struct {
+	Id        int64     `sql:"id"`
+	Name      string    `sql:"name"`
+	Age       int       `sql:"age"`
+	CreatedAt time.Time `sql:"created_at"`
+	Nickname  string    `sql:"nickname"`
+	Tags      []string  `sql:"tags;json"`
+}
+
+func (checkUser) TableName() string {
+	return "user"
+}
+
+func (checkUser) PrimaryKey() string {
+	return "id"
+}
+
+type checkOrder struct {
+	Id int64 `sql:"id"`
+}
+
+func (checkOrder) TableName() string {
+	return "order"
+}
+
+func (checkOrder) PrimaryKey() string {
+	return "id"
+}
+
+func TestCheckSchema(t *testing.T) {
+	var db = openFakeDB(t, sqlite.Dialect(), map[string]dbstest.Result{
+		"name = ?": {Columns: []string{"name"}, Rows: [][]driver.Value{{"user"}}},
+		"pragma_table_info": {Columns: []string{"name", "type", "notnull", "dflt_value", "pk"}, Rows: [][]driver.Value{
+			{"id", "INTEGER", int64(1), nil, int64(1)},
+			{"name", "VARCHAR(64)", int64(1), nil, int64(0)},
+			{"age", "DATETIME", int64(0), nil, int64(0)},
+			{"created_at", "DATETIME", int64(1), "CURRENT_TIMESTAMP", int64(0)},
+			{"tags", "TEXT", int64(0), nil, int64(0)},
+			{"email", "VARCHAR(128)", int64(1), nil, int64(0)},
+			{"status", "INTEGER", int64(1), "0", int64(0)},
+		}},
+	})
+
+	var err = schema.CheckSchema(context.Background(), db, checkUser{})
+	var drift *schema.DriftError
+	if !errors.As(err, &drift) || !errors.Is(err, schema.ErrSchemaDrift) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", schema.ErrSchemaDrift, err)
+	}
+
+	var actual = make(map[string]schema.ProblemKind)
+	for _, problem := range drift.Problems {
+		actual[problem.Column] = problem.Kind
+	}
+	var expect = map[string]schema.ProblemKind{
+		"age":      schema.IncompatibleType,
+		"nickname": schema.MissingColumn,
+		"email":    schema.RequiredColumn,
+	}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Fatalf("期望: %v, 实际: %v", expect, actual)
+	}
+}
+
+func TestCheckSchema_MissingTable(t *testing.T) {
+	var db = openFakeDB(t, sqlite.Dialect(), nil)
+
+	var err = schema.CheckSchema(context.Background(), db, checkOrder{})
+	var drift *schema.DriftError
+	if !errors.As(err, &drift) || len(drift.Problems) != 1 || drift.Problems[0].Kind != schema.MissingTable {
+		t.Fatalf("期望错误: %v, 实际错误: %v", schema.MissingTable, err)
+	}
+}
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"github.com/smartwalle/dbs"
+)
+
+// CreateTable 根据表结构生成 CreateTableBuilder，可以用于导出表结构：
+//
+//	var tables, err = schema.Inspect(ctx, db)
+//	for _, table := range tables {
+//		query, _, err := schema.CreateTable(table).UseDialect(db.Dialect()).SQL()
+//	}
+//
+// 列类型使用数据库中的完整类型，自增列会忽略默认值（如 PostgreSQL 的 nextval()），不包括索引、外键以及注释。
+func CreateTable(table *Table) *dbs.CreateTableBuilder {
+	var cb = dbs.NewCreateTableBuilder()
+	cb.Table(table.Name)
+	for _, column := range table.Columns {
+		var def = dbs.ColumnDef{
+			Name:          column.Name,
+			Type:          column.Type,
+			NotNull:       !column.Nullable,
+			AutoIncrement: column.AutoIncrement,
+		}
+		if column.Default.Valid && !column.AutoIncrement {
+			def.Default = column.Default.String
+		}
+		cb.Column(def)
+	}
+	cb.PrimaryKey(table.PrimaryKey...)
+	return cb
+}
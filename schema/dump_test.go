@@ -0,0 +1,30 @@
+package schema_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/schema"
+)
+
+func TestCreateTable(t *testing.T) {
+	var table = &schema.Table{
+		Name: "user",
+		Columns: []*schema.Column{
+			{Name: "id", Type: "bigint", AutoIncrement: true, Default: sql.NullString{String: "nextval('user_id_seq'::regclass)", Valid: true}},
+			{Name: "name", Type: "varchar(64)", Default: sql.NullString{String: "''", Valid: true}},
+			{Name: "nickname", Type: "text", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+
+	var query, _, err = schema.CreateTable(table).UseDialect(postgres.Dialect()).SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expect = "CREATE TABLE user (id bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, name varchar(64) NOT NULL DEFAULT '', nickname text, PRIMARY KEY (id))"
+	if query != expect {
+		t.Fatalf("期望 SQL: %s, 实际 SQL: %s", expect, query)
+	}
+}
@@ -0,0 +1,326 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoType 为列映射的 Go 类型。
+type GoType struct {
+	Name   string // 类型名称，如 int64、time.Time、decimal.Decimal
+	Import string // 需要导入的包，如 time、github.com/shopspring/decimal
+	Tag    string // 需要追加的 sql 标签选项，如 array
+}
+
+// TypeFunc 将列映射为 Go 类型，返回 false 时使用其它规则。
+type TypeFunc func(table *Table, column *Column) (GoType, bool)
+
+var (
+	goBool    = GoType{Name: "bool"}
+	goInt8    = GoType{Name: "int8"}
+	goInt16   = GoType{Name: "int16"}
+	goInt32   = GoType{Name: "int32"}
+	goInt64   = GoType{Name: "int64"}
+	goFloat32 = GoType{Name: "float32"}
+	goFloat64 = GoType{Name: "float64"}
+	goString  = GoType{Name: "string"}
+	goBytes   = GoType{Name: "[]byte"}
+	goTime    = GoType{Name: "time.Time", Import: "time"}
+)
+
+// defaultTypes 为 DataType 到 Go 类型的默认映射，未列出的类型映射为 any。
+var defaultTypes = map[string]GoType{
+	"bool":    goBool,
+	"boolean": goBool,
+
+	"tinyint":   goInt8,
+	"smallint":  goInt16,
+	"mediumint": goInt32,
+	"int":       goInt32,
+	"integer":   goInt32,
+	"serial":    goInt32,
+	"bigint":    goInt64,
+	"bigserial": goInt64,
+	"year":      goInt16,
+
+	"float":            goFloat32,
+	"real":             goFloat32,
+	"double":           goFloat64,
+	"double precision": goFloat64,
+	"decimal":          goFloat64,
+	"numeric":          goFloat64,
+
+	"char":       goString,
+	"varchar":    goString,
+	"tinytext":   goString,
+	"text":       goString,
+	"mediumtext": goString,
+	"longtext":   goString,
+	"clob":       goString,
+	"enum":       goString,
+	"set":        goString,
+	"json":       goString,
+	"jsonb":      goString,
+	"uuid":       goString,
+	"xml":        goString,
+	"citext":     goString,
+	"inet":       goString,
+	"cidr":       goString,
+	"time":       goString,
+
+	"date":        goTime,
+	"datetime":    goTime,
+	"timestamp":   goTime,
+	"timestamptz": goTime,
+
+	"binary":     goBytes,
+	"varbinary":  goBytes,
+	"tinyblob":   goBytes,
+	"blob":       goBytes,
+	"mediumblob": goBytes,
+	"longblob":   goBytes,
+	"bytea":      goBytes,
+}
+
+// arrayTypes 为 PostgreSQL 数组元素类型到 Go 切片的映射，对应 sql 标签的 array 选项。
+var arrayTypes = map[string]GoType{
+	"boolean":          {Name: "[]bool", Tag: "array"},
+	"smallint":         {Name: "[]int64", Tag: "array"},
+	"integer":          {Name: "[]int64", Tag: "array"},
+	"bigint":           {Name: "[]int64", Tag: "array"},
+	"real":             {Name: "[]float64", Tag: "array"},
+	"double precision": {Name: "[]float64", Tag: "array"},
+	"numeric":          {Name: "[]float64", Tag: "array"},
+	"char":             {Name: "[]string", Tag: "array"},
+	"varchar":          {Name: "[]string", Tag: "array"},
+	"text":             {Name: "[]string", Tag: "array"},
+	"uuid":             {Name: "[]string", Tag: "array"},
+	"timestamp":        {Name: "[]time.Time", Import: "time", Tag: "array"},
+	"timestamptz":      {Name: "[]time.Time", Import: "time", Tag: "array"},
+	"date":             {Name: "[]time.Time", Import: "time", Tag: "array"},
+}
+
+type GeneratorOption func(g *Generator)
+
+// WithType 覆盖 DataType（如 decimal、jsonb）的默认映射，可以为 NULL 的列仍然会使用指针类型。
+func WithType(dataType string, goType GoType) GeneratorOption {
+	return func(g *Generator) {
+		g.types[strings.ToLower(dataType)] = goType
+	}
+}
+
+// WithTypeFunc 优先于 WithType 以及默认规则，返回的类型不会再转换为指针类型。
+func WithTypeFunc(fn TypeFunc) GeneratorOption {
+	return func(g *Generator) {
+		g.typeFunc = fn
+	}
+}
+
+// WithNaming 指定表名以及列名转换为结构体名以及字段名的规则，默认为 Camel。
+func WithNaming(naming func(name string) string) GeneratorOption {
+	return func(g *Generator) {
+		if naming != nil {
+			g.naming = naming
+		}
+	}
+}
+
+// Generator 根据表结构生成带有 sql 标签以及 TableName()、PrimaryKey() 方法的结构体。
+//
+//	var tables, err = schema.Inspect(ctx, db)
+//	src, err := schema.NewGenerator("model", schema.WithType("decimal", schema.GoType{Name: "decimal.Decimal", Import: "github.com/shopspring/decimal"})).Generate(tables...)
+//
+// 默认的类型映射：
+//   - 整数映射为对应位数的 intN，无符号整数映射为 uintN，MySQL 的 tinyint(1) 映射为 bool；
+//   - 浮点数以及 decimal、numeric 映射为 float64（real、float 为 float32）；
+//   - 字符、枚举、json、uuid 等映射为 string，date、datetime、timestamp 映射为 time.Time；
+//   - 二进制类型映射为 []byte，PostgreSQL 数组映射为切片并设置 array 标签选项，其它类型映射为 any；
+//   - 可以为 NULL 的列使用指针类型（切片以及 any 除外）。
+//
+// 复合主键的列会设置 pk 标签选项，有默认值或者自增的列会设置 default 标签选项。
+type Generator struct {
+	pkg      string
+	types    map[string]GoType
+	typeFunc TypeFunc
+	naming   func(name string) string
+}
+
+func NewGenerator(pkg string, opts ...GeneratorOption) *Generator {
+	var g = &Generator{}
+	g.pkg = pkg
+	g.types = make(map[string]GoType)
+	g.naming = Camel
+	for _, opt := range opts {
+		if opt != nil {
+			opt(g)
+		}
+	}
+	return g
+}
+
+// Generate 按照 tables 的顺序生成结构体，返回格式化之后的源码。
+func (g *Generator) Generate(tables ...*Table) ([]byte, error) {
+	var imports = make(map[string]bool)
+	var body bytes.Buffer
+	for _, table := range tables {
+		g.writeTable(&body, table, imports)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+	if len(imports) > 0 {
+		var groups [2][]string
+		for path := range imports {
+			if elem, _, _ := strings.Cut(path, "/"); strings.Contains(elem, ".") {
+				groups[1] = append(groups[1], path)
+			} else {
+				groups[0] = append(groups[0], path)
+			}
+		}
+
+		buf.WriteString("import (\n")
+		for idx, group := range groups {
+			if idx > 0 && len(group) > 0 && len(groups[0]) > 0 {
+				buf.WriteString("\n")
+			}
+			sort.Strings(group)
+			for _, path := range group {
+				fmt.Fprintf(&buf, "\t%q\n", path)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	var src, err = format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("schema: format generated code: %w", err)
+	}
+	return src, nil
+}
+
+func (g *Generator) writeTable(w *bytes.Buffer, table *Table, imports map[string]bool) {
+	var name = exportedName(g.naming(table.Name), "T")
+
+	if table.Comment != "" {
+		fmt.Fprintf(w, "// %s %s\n", name, singleLine(table.Comment))
+	} else {
+		fmt.Fprintf(w, "// %s 映射到表 %s。\n", name, table.Name)
+	}
+	fmt.Fprintf(w, "type %s struct {\n", name)
+
+	var used = make(map[string]int)
+	for _, column := range table.Columns {
+		var fieldName = exportedName(g.naming(column.Name), "C")
+		if used[fieldName]++; used[fieldName] > 1 {
+			fieldName += strconv.Itoa(used[fieldName])
+		}
+
+		var goType = g.goType(table, column)
+		if goType.Import != "" {
+			imports[goType.Import] = true
+		}
+
+		var options = []string{column.Name}
+		if column.PrimaryKey && len(table.PrimaryKey) > 1 {
+			options = append(options, "pk")
+		}
+		if column.HasDefault() {
+			options = append(options, "default")
+		}
+		if goType.Tag != "" {
+			options = append(options, goType.Tag)
+		}
+
+		fmt.Fprintf(w, "%s %s `sql:%q`", fieldName, goType.Name, strings.Join(options, ";"))
+		if column.Comment != "" {
+			fmt.Fprintf(w, " // %s", singleLine(column.Comment))
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, "func (%s) TableName() string {\n\treturn %q\n}\n\n", name, table.Name)
+
+	var primaryKey string
+	if len(table.PrimaryKey) > 0 {
+		primaryKey = table.PrimaryKey[0]
+	}
+	fmt.Fprintf(w, "func (%s) PrimaryKey() string {\n\treturn %q\n}\n\n", name, primaryKey)
+}
+
+// goType 依次使用 WithTypeFunc、WithType 以及默认规则获取列映射的 Go 类型。
+func (g *Generator) goType(table *Table, column *Column) GoType {
+	if g.typeFunc != nil {
+		if goType, ok := g.typeFunc(table, column); ok {
+			return goType
+		}
+	}
+
+	var goType, ok = g.types[column.DataType]
+	switch {
+	case column.Array:
+		if goType, ok = arrayTypes[column.DataType]; !ok {
+			// 无法映射为切片的数组使用字符串形式，如 {a,b}
+			goType = goString
+		}
+	case ok:
+	case strings.HasPrefix(strings.ToLower(column.Type), "tinyint(1)"):
+		goType = goBool
+	default:
+		if goType, ok = defaultTypes[column.DataType]; !ok {
+			return GoType{Name: "any"}
+		}
+		if column.Unsigned && strings.HasPrefix(goType.Name, "int") {
+			goType.Name = "u" + goType.Name
+		}
+	}
+
+	if column.Nullable && !strings.HasPrefix(goType.Name, "[]") && goType.Name != "any" {
+		goType.Name = "*" + goType.Name
+	}
+	return goType
+}
+
+// Camel 将下划线命名转换为驼峰命名，如 user_id 转换为 UserId，created_at 转换为 CreatedAt。
+func Camel(name string) string {
+	var builder strings.Builder
+	builder.Grow(len(name))
+	var upper = true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// exportedName 确保 name 为可以导出的标识符，不以大写字母开头时添加 prefix。
+func exportedName(name, prefix string) string {
+	var runes = []rune(name)
+	for idx, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			runes[idx] = '_'
+		}
+	}
+	name = string(runes)
+	if name == "" || !unicode.IsUpper(runes[0]) {
+		name = prefix + name
+	}
+	return name
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,74 @@
+package schema_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/smartwalle/dbs/schema"
+)
+
+func TestGenerator(t *testing.T) {
+	var tables = []*schema.Table{
+		{
+			Name:       "user_role",
+			Comment:    "用户角色",
+			PrimaryKey: []string{"user_id", "role_id"},
+			Columns: []*schema.Column{
+				{Name: "user_id", Type: "bigint unsigned", DataType: "bigint", Unsigned: true, PrimaryKey: true},
+				{Name: "role_id", Type: "bigint", DataType: "bigint", PrimaryKey: true},
+				{Name: "enabled", Type: "tinyint(1)", DataType: "tinyint", Default: sql.NullString{String: "1", Valid: true}},
+				{Name: "price", Type: "decimal(10,2)", DataType: "decimal", Nullable: true, Comment: "价格"},
+				{Name: "tags", Type: "text[]", DataType: "text", Array: true, Nullable: true},
+				{Name: "created_at", Type: "timestamp", DataType: "timestamp", Nullable: true},
+				{Name: "geom", Type: "geometry", DataType: "geometry"},
+			},
+		},
+	}
+
+	var src, err = schema.NewGenerator("model",
+		schema.WithType("decimal", schema.GoType{Name: "decimal.Decimal", Import: "github.com/shopspring/decimal"}),
+		schema.WithTypeFunc(func(table *schema.Table, column *schema.Column) (schema.GoType, bool) {
+			if column.Name == "role_id" {
+				return schema.GoType{Name: "RoleId"}, true
+			}
+			return schema.GoType{}, false
+		}),
+	).Generate(tables...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out = string(src)
+	for _, expect := range []string{
+		"import (\n\t\"time\"\n\n\t\"github.com/shopspring/decimal\"\n)",
+		"// UserRole 用户角色\ntype UserRole struct {",
+		"UserId    uint64           `sql:\"user_id;pk\"`",
+		"RoleId    RoleId           `sql:\"role_id;pk\"`",
+		"Enabled   bool             `sql:\"enabled;default\"`",
+		"Price     *decimal.Decimal `sql:\"price\"` // 价格",
+		"Tags      []string         `sql:\"tags;array\"`",
+		"CreatedAt *time.Time       `sql:\"created_at\"`",
+		"Geom      any              `sql:\"geom\"`",
+		"func (UserRole) TableName() string {\n\treturn \"user_role\"\n}",
+		"func (UserRole) PrimaryKey() string {\n\treturn \"user_id\"\n}",
+	} {
+		if !strings.Contains(out, expect) {
+			t.Fatalf("期望包含: %s, 实际:\n%s", expect, out)
+		}
+	}
+}
+
+func TestCamel(t *testing.T) {
+	var tests = map[string]string{
+		"id":         "Id",
+		"user_id":    "UserId",
+		"created-at": "CreatedAt",
+		"Name":       "Name",
+	}
+	for name, expect := range tests {
+		if actual := schema.Camel(name); actual != expect {
+			t.Fatalf("%s 期望: %s, 实际: %s", name, expect, actual)
+		}
+	}
+}
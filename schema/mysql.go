@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/smartwalle/dbs"
+)
+
+const (
+	kMySQLTables = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name"
+
+	kMySQLTable = "SELECT table_comment FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+
+	kMySQLColumns = "SELECT column_name, column_type, data_type, is_nullable, column_default, extra, character_maximum_length, column_comment " +
+		"FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position"
+
+	kMySQLPrimaryKey = "SELECT column_name FROM information_schema.key_column_usage " +
+		"WHERE table_schema = DATABASE() AND table_name = ? AND constraint_name = 'PRIMARY' ORDER BY ordinal_position"
+)
+
+// mysqlInspector 通过 information_schema 读取 MySQL 的表结构。
+type mysqlInspector struct {
+}
+
+func (mysqlInspector) Tables(ctx context.Context, session dbs.Session) ([]string, error) {
+	return queryStrings(ctx, session, kMySQLTables)
+}
+
+func (mysqlInspector) Table(ctx context.Context, session dbs.Session, name string) (*Table, error) {
+	var comments, err = queryStrings(ctx, session, kMySQLTable, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, ErrTableNotFound
+	}
+
+	var table = &Table{Name: name, Comment: comments[0]}
+
+	rows, err := session.QueryContext(ctx, kMySQLColumns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column = &Column{}
+		var dataType, nullable, extra string
+		var length sql.NullInt64
+		if err = rows.Scan(&column.Name, &column.Type, &dataType, &nullable, &column.Default, &extra, &length, &column.Comment); err != nil {
+			return nil, err
+		}
+		_, _, column.Unsigned = parseType(column.Type)
+		column.DataType = strings.ToLower(dataType)
+		column.Length = length.Int64
+		column.Nullable = nullable == "YES"
+		column.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keys, err := queryStrings(ctx, session, kMySQLPrimaryKey, name)
+	if err != nil {
+		return nil, err
+	}
+	setPrimaryKey(table, keys)
+	return table, nil
+}
@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/smartwalle/dbs"
+)
+
+const (
+	kPostgresTables = "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name"
+
+	kPostgresTable = "SELECT COALESCE(obj_description(c.oid, 'pg_class'), '') FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE n.nspname = current_schema() AND c.relname = $1 AND c.relkind IN ('r', 'p')"
+
+	kPostgresColumns = "SELECT c.column_name, c.data_type, c.udt_name, c.is_nullable, c.column_default, c.is_identity, c.character_maximum_length, " +
+		"COALESCE(col_description(to_regclass(quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::oid, c.ordinal_position::int), '') " +
+		"FROM information_schema.columns c WHERE c.table_schema = current_schema() AND c.table_name = $1 ORDER BY c.ordinal_position"
+
+	kPostgresPrimaryKey = "SELECT k.column_name FROM information_schema.table_constraints t " +
+		"JOIN information_schema.key_column_usage k ON k.constraint_schema = t.constraint_schema AND k.constraint_name = t.constraint_name AND k.table_name = t.table_name " +
+		"WHERE t.table_schema = current_schema() AND t.table_name = $1 AND t.constraint_type = 'PRIMARY KEY' ORDER BY k.ordinal_position"
+)
+
+// postgresTypes 将 udt_name 转换为常用的类型名称，其它类型（如 varchar、text、timestamp、jsonb）直接使用 udt_name。
+var postgresTypes = map[string]string{
+	"int2":   "smallint",
+	"int4":   "integer",
+	"int8":   "bigint",
+	"float4": "real",
+	"float8": "double precision",
+	"bool":   "boolean",
+	"bpchar": "char",
+}
+
+// postgresInspector 通过 information_schema 以及 pg_catalog 读取 PostgreSQL 的表结构，只读取 current_schema() 中的表。
+type postgresInspector struct {
+}
+
+func (postgresInspector) Tables(ctx context.Context, session dbs.Session) ([]string, error) {
+	return queryStrings(ctx, session, kPostgresTables)
+}
+
+func (postgresInspector) Table(ctx context.Context, session dbs.Session, name string) (*Table, error) {
+	var comments, err = queryStrings(ctx, session, kPostgresTable, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, ErrTableNotFound
+	}
+
+	var table = &Table{Name: name, Comment: comments[0]}
+
+	rows, err := session.QueryContext(ctx, kPostgresColumns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column = &Column{}
+		var dataType, udtName, nullable, identity string
+		var length sql.NullInt64
+		if err = rows.Scan(&column.Name, &dataType, &udtName, &nullable, &column.Default, &identity, &length, &column.Comment); err != nil {
+			return nil, err
+		}
+
+		column.Array = dataType == "ARRAY"
+		column.DataType = postgresDataType(dataType, udtName)
+		column.Type = column.DataType
+		if length.Valid {
+			column.Length = length.Int64
+			column.Type += "(" + strconv.FormatInt(length.Int64, 10) + ")"
+		}
+		if column.Array {
+			column.Type += "[]"
+		}
+		column.Nullable = nullable == "YES"
+		column.AutoIncrement = identity == "YES" || strings.HasPrefix(column.Default.String, "nextval(")
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keys, err := queryStrings(ctx, session, kPostgresPrimaryKey, name)
+	if err != nil {
+		return nil, err
+	}
+	setPrimaryKey(table, keys)
+	return table, nil
+}
+
+// postgresDataType 根据 udt_name 返回基础类型，数组返回元素的类型，自定义类型（如枚举）返回类型名称。
+func postgresDataType(dataType, udtName string) string {
+	if dataType == "ARRAY" {
+		udtName = strings.TrimPrefix(udtName, "_")
+	}
+	if name, ok := postgresTypes[udtName]; ok {
+		return name
+	}
+	return udtName
+}
@@ -0,0 +1,178 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/smartwalle/dbs"
+)
+
+var (
+	ErrUnknownDialect = errors.New("schema: unknown dialect")
+	ErrTableNotFound  = errors.New("schema: table not found")
+)
+
+// Table 为与数据库无关的表结构。
+type Table struct {
+	Name       string
+	Comment    string
+	Columns    []*Column
+	PrimaryKey []string // 按照主键中的顺序排列
+}
+
+// Column 返回名为 name 的列，不存在时返回 nil。
+func (t *Table) Column(name string) *Column {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// Column 为与数据库无关的列结构。
+type Column struct {
+	Name string
+
+	// Type 为数据库中的完整类型，如 varchar(64)、int unsigned、integer[]
+	Type string
+
+	// DataType 为小写的基础类型，如 varchar、int、bigint、timestamp，数组为元素的类型
+	DataType string
+
+	Length        int64 // 字符类型的最大长度，如 varchar(64) 为 64，没有限制或者不是字符类型时为 0
+	Unsigned      bool
+	Array         bool
+	Nullable      bool
+	Default       sql.NullString // 默认值表达式，如 0、'draft'、CURRENT_TIMESTAMP
+	AutoIncrement bool
+	PrimaryKey    bool
+	Comment       string
+}
+
+// HasDefault 判断插入时省略该列是否可以由数据库生成值（设置了默认值或者为自增列）。
+func (c *Column) HasDefault() bool {
+	return c.Default.Valid || c.AutoIncrement
+}
+
+// Inspector 用于读取某一种数据库的表结构。
+type Inspector interface {
+	// Tables 返回当前数据库（schema）中所有的表名，按照名称排序。
+	Tables(ctx context.Context, session dbs.Session) ([]string, error)
+
+	// Table 返回表结构，表不存在时返回 ErrTableNotFound。
+	Table(ctx context.Context, session dbs.Session, name string) (*Table, error)
+}
+
+var inspectors = struct {
+	sync.RWMutex
+	m map[string]Inspector
+}{
+	m: map[string]Inspector{
+		"mysql":    mysqlInspector{},
+		"postgres": postgresInspector{},
+		"sqlite":   sqliteInspector{},
+	},
+}
+
+// RegisterInspector 注册 Inspector，name 为 dbs.DialectName() 返回的名称，已经注册的 Inspector 会被替换。
+func RegisterInspector(name string, inspector Inspector) {
+	inspectors.Lock()
+	inspectors.m[name] = inspector
+	inspectors.Unlock()
+}
+
+// InspectorOf 返回 Dialect 对应的 Inspector，内置了 mysql、postgres 以及 sqlite 的实现。
+//
+// 没有调用 UseDialect() 的 DB 无法区分数据库，会返回 ErrUnknownDialect。
+func InspectorOf(dialect dbs.Dialect) (Inspector, error) {
+	var name = dbs.DialectName(dialect)
+
+	inspectors.RLock()
+	var inspector, ok = inspectors.m[name]
+	inspectors.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDialect, name)
+	}
+	return inspector, nil
+}
+
+// Inspect 读取 tables 的表结构，tables 为空时读取所有的表。
+func Inspect(ctx context.Context, session dbs.Session, tables ...string) ([]*Table, error) {
+	var inspector, err = InspectorOf(session.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tables) == 0 {
+		if tables, err = inspector.Tables(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	var nTables = make([]*Table, 0, len(tables))
+	for _, name := range tables {
+		var table, err = inspector.Table(ctx, session, name)
+		if err != nil {
+			return nil, err
+		}
+		nTables = append(nTables, table)
+	}
+	return nTables, nil
+}
+
+// queryStrings 执行只返回一列字符串的查询。
+func queryStrings(ctx context.Context, session dbs.Session, query string, args ...any) ([]string, error) {
+	var rows, err = session.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// setPrimaryKey 设置表的主键，并标记对应的列。
+func setPrimaryKey(table *Table, keys []string) {
+	table.PrimaryKey = keys
+	for _, key := range keys {
+		if column := table.Column(key); column != nil {
+			column.PrimaryKey = true
+		}
+	}
+}
+
+// parseType 解析 varchar(64)、decimal(10,2)、int(11) unsigned 这样的类型，返回小写的基础类型、长度以及是否为无符号类型。
+func parseType(raw string) (dataType string, length int64, unsigned bool) {
+	dataType = strings.ToLower(strings.TrimSpace(raw))
+	if idx := strings.Index(dataType, " unsigned"); idx >= 0 {
+		unsigned = true
+		dataType = dataType[:idx] + dataType[idx+len(" unsigned"):]
+	}
+	dataType = strings.TrimSpace(strings.Replace(dataType, " zerofill", "", 1))
+
+	if begin := strings.IndexByte(dataType, '('); begin >= 0 {
+		if end := strings.IndexByte(dataType[begin:], ')'); end >= 0 {
+			var args = dataType[begin+1 : begin+end]
+			if first, _, _ := strings.Cut(args, ","); first != "" {
+				length, _ = strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+			}
+			dataType = strings.TrimSpace(dataType[:begin] + dataType[begin+end+1:])
+		}
+	}
+	return dataType, length, unsigned
+}
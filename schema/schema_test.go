@@ -0,0 +1,129 @@
+package schema_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/mysql"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/dialect/sqlite"
+	"github.com/smartwalle/dbs/internal/dbstest"
+	"github.com/smartwalle/dbs/schema"
+)
+
+func openFakeDB(t *testing.T, dialect dbs.Dialect, results map[string]dbstest.Result) *dbs.DB {
+	var db = dbstest.Open(t, dbstest.NewDriver(results))
+	db.UseDialect(dialect)
+	return db
+}
+
+func TestInspect_MySQL(t *testing.T) {
+	var db = openFakeDB(t, mysql.Dialect(), map[string]dbstest.Result{
+		"table_type = 'BASE TABLE'": {Columns: []string{"table_name"}, Rows: [][]driver.Value{{"user"}}},
+		"SELECT table_comment":      {Columns: []string{"table_comment"}, Rows: [][]driver.Value{{"用户"}}},
+		"information_schema.columns": {Columns: []string{"column_name", "column_type", "data_type", "is_nullable", "column_default", "extra", "character_maximum_length", "column_comment"}, Rows: [][]driver.Value{
+			{"id", "bigint unsigned", "bigint", "NO", nil, "auto_increment", nil, ""},
+			{"name", "varchar(64)", "varchar", "NO", "", "", int64(64), "名称"},
+			{"enabled", "tinyint(1)", "tinyint", "YES", "1", "", nil, ""},
+		}},
+		"key_column_usage": {Columns: []string{"column_name"}, Rows: [][]driver.Value{{"id"}}},
+	})
+
+	var tables, err = schema.Inspect(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Name != "user" || tables[0].Comment != "用户" {
+		t.Fatalf("期望: user 用户, 实际: %+v", tables)
+	}
+
+	var table = tables[0]
+	if !reflect.DeepEqual(table.PrimaryKey, []string{"id"}) {
+		t.Fatalf("期望主键: [id], 实际主键: %v", table.PrimaryKey)
+	}
+	var id = table.Column("id")
+	if id.DataType != "bigint" || !id.Unsigned || !id.AutoIncrement || !id.PrimaryKey || id.Nullable {
+		t.Fatalf("id 列不符合预期: %+v", id)
+	}
+	var name = table.Column("name")
+	if name.Length != 64 || name.Comment != "名称" || !name.Default.Valid || name.Default.String != "" {
+		t.Fatalf("name 列不符合预期: %+v", name)
+	}
+	if enabled := table.Column("enabled"); !enabled.Nullable || enabled.Default.String != "1" {
+		t.Fatalf("enabled 列不符合预期: %+v", enabled)
+	}
+}
+
+func TestInspect_Postgres(t *testing.T) {
+	var db = openFakeDB(t, postgres.Dialect(), map[string]dbstest.Result{
+		"obj_description": {Columns: []string{"comment"}, Rows: [][]driver.Value{{""}}},
+		"information_schema.columns": {Columns: []string{"column_name", "data_type", "udt_name", "is_nullable", "column_default", "is_identity", "character_maximum_length", "comment"}, Rows: [][]driver.Value{
+			{"id", "integer", "int4", "NO", "nextval('user_id_seq'::regclass)", "NO", nil, ""},
+			{"tags", "ARRAY", "_text", "YES", nil, "NO", nil, ""},
+			{"email", "character varying", "varchar", "NO", nil, "NO", int64(128), ""},
+			{"created_at", "timestamp with time zone", "timestamptz", "NO", "now()", "NO", nil, ""},
+		}},
+		"PRIMARY KEY": {Columns: []string{"column_name"}, Rows: [][]driver.Value{{"id"}}},
+	})
+
+	var tables, err = schema.Inspect(context.Background(), db, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table = tables[0]
+	if id := table.Column("id"); id.DataType != "integer" || !id.AutoIncrement || !id.PrimaryKey {
+		t.Fatalf("id 列不符合预期: %+v", id)
+	}
+	if tags := table.Column("tags"); tags.DataType != "text" || !tags.Array || tags.Type != "text[]" {
+		t.Fatalf("tags 列不符合预期: %+v", tags)
+	}
+	if email := table.Column("email"); email.Type != "varchar(128)" || email.Length != 128 {
+		t.Fatalf("email 列不符合预期: %+v", email)
+	}
+	if createdAt := table.Column("created_at"); createdAt.DataType != "timestamptz" || !createdAt.HasDefault() {
+		t.Fatalf("created_at 列不符合预期: %+v", createdAt)
+	}
+}
+
+func TestInspect_SQLite(t *testing.T) {
+	var db = openFakeDB(t, sqlite.Dialect(), map[string]dbstest.Result{
+		"name = ?": {Columns: []string{"name"}, Rows: [][]driver.Value{{"user_role"}}},
+		"pragma_table_info": {Columns: []string{"name", "type", "notnull", "dflt_value", "pk"}, Rows: [][]driver.Value{
+			{"role_id", "INTEGER", int64(1), nil, int64(2)},
+			{"user_id", "INTEGER", int64(1), nil, int64(1)},
+			{"note", "VARCHAR(255)", int64(0), "''", int64(0)},
+		}},
+	})
+
+	var tables, err = schema.Inspect(context.Background(), db, "user_role")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table = tables[0]
+	if !reflect.DeepEqual(table.PrimaryKey, []string{"user_id", "role_id"}) {
+		t.Fatalf("期望主键: [user_id role_id], 实际主键: %v", table.PrimaryKey)
+	}
+	// 复合主键不是 rowid 的别名
+	if userId := table.Column("user_id"); userId.DataType != "bigint" || userId.AutoIncrement {
+		t.Fatalf("user_id 列不符合预期: %+v", userId)
+	}
+	if note := table.Column("note"); note.DataType != "varchar" || note.Length != 255 || !note.Nullable {
+		t.Fatalf("note 列不符合预期: %+v", note)
+	}
+}
+
+func TestInspect_Error(t *testing.T) {
+	var db = openFakeDB(t, nil, nil)
+	if _, err := schema.Inspect(context.Background(), db); !errors.Is(err, schema.ErrUnknownDialect) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", schema.ErrUnknownDialect, err)
+	}
+
+	db = openFakeDB(t, sqlite.Dialect(), nil)
+	if _, err := schema.Inspect(context.Background(), db, "user"); !errors.Is(err, schema.ErrTableNotFound) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", schema.ErrTableNotFound, err)
+	}
+}
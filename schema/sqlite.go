@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"context"
+	"strings"
+
+	"github.com/smartwalle/dbs"
+)
+
+const (
+	kSQLiteTables = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+
+	kSQLiteTable = "SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?"
+
+	kSQLiteColumns = "SELECT name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid"
+)
+
+// sqliteInspector 通过 PRAGMA table_info 读取 SQLite 的表结构，需要 SQLite 3.16.0 及以上的版本。
+//
+// SQLite 的 INTEGER 为 64 位整数，声明为 int 或者 integer 的列 DataType 为 bigint。
+type sqliteInspector struct {
+}
+
+func (sqliteInspector) Tables(ctx context.Context, session dbs.Session) ([]string, error) {
+	return queryStrings(ctx, session, kSQLiteTables)
+}
+
+func (sqliteInspector) Table(ctx context.Context, session dbs.Session, name string) (*Table, error) {
+	var names, err = queryStrings(ctx, session, kSQLiteTable, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, ErrTableNotFound
+	}
+
+	var table = &Table{Name: name}
+
+	rows, err := session.QueryContext(ctx, kSQLiteColumns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// pk 为列在主键中的位置（从 1 开始），不属于主键时为 0
+	var positions = make(map[int]string)
+	for rows.Next() {
+		var column = &Column{}
+		var notNull, pk int
+		if err = rows.Scan(&column.Name, &column.Type, &notNull, &column.Default, &pk); err != nil {
+			return nil, err
+		}
+		column.DataType, column.Length, column.Unsigned = parseType(column.Type)
+		if column.DataType == "int" || column.DataType == "integer" {
+			column.DataType = "bigint"
+		}
+		column.Nullable = notNull == 0 && pk == 0
+		if pk > 0 {
+			positions[pk] = column.Name
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys = make([]string, 0, len(positions))
+	for idx := 1; idx <= len(positions); idx++ {
+		keys = append(keys, positions[idx])
+	}
+	setPrimaryKey(table, keys)
+
+	// 类型为 INTEGER 的单列主键是 rowid 的别名，插入时会自动生成
+	if len(keys) == 1 {
+		if column := table.Column(keys[0]); strings.EqualFold(column.Type, "integer") {
+			column.AutoIncrement = true
+		}
+	}
+	return table, nil
+}
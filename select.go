@@ -8,6 +8,7 @@ import (
 type SelectBuilder struct {
 	dialect  Dialect
 	session  Session
+	strategy SliceStrategy
 	prefixes *Clauses
 	options  *Clauses
 	columns  *Clauses
@@ -28,9 +29,13 @@ func NewSelectBuilder() *SelectBuilder {
 }
 
 func (sb *SelectBuilder) Clone() *SelectBuilder {
+	if sb == nil {
+		return nil
+	}
 	var nsb = &SelectBuilder{}
 	nsb.dialect = sb.dialect
 	nsb.session = sb.session
+	nsb.strategy = sb.strategy
 	nsb.prefixes = sb.prefixes.Clone()
 	nsb.options = sb.options.Clone()
 	nsb.columns = sb.columns.Clone()
@@ -40,8 +45,8 @@ func (sb *SelectBuilder) Clone() *SelectBuilder {
 	nsb.groupBys = sb.groupBys.Clone()
 	nsb.having = sb.having.Clone()
 	nsb.orderBys = sb.orderBys.Clone()
-	nsb.limit = sb.limit
-	nsb.offset = sb.offset
+	nsb.limit = clone(sb.limit)
+	nsb.offset = clone(sb.offset)
 	nsb.suffixes = sb.suffixes.Clone()
 	return nsb
 }
@@ -49,6 +54,7 @@ func (sb *SelectBuilder) Clone() *SelectBuilder {
 func (sb *SelectBuilder) Reset() {
 	sb.dialect = nil
 	sb.session = nil
+	sb.strategy = SliceDefault
 	sb.prefixes.reset()
 	sb.options.reset()
 	sb.columns.reset()
@@ -76,6 +82,11 @@ func (sb *SelectBuilder) UseSession(session Session) *SelectBuilder {
 	return sb
 }
 
+func (sb *SelectBuilder) UseSliceStrategy(strategy SliceStrategy) *SelectBuilder {
+	sb.strategy = strategy
+	return sb
+}
+
 func (sb *SelectBuilder) Prefix(sql any, args ...any) *SelectBuilder {
 	if sb.prefixes == nil {
 		sb.prefixes = NewClauses(' ')
@@ -96,6 +107,21 @@ func (sb *SelectBuilder) Selects(columns ...string) *SelectBuilder {
 	return sb.Select(Parts(columns))
 }
 
+// SelectsOf 将 src 映射的列名添加到结果列中，src 可以为结构体、结构体指针或者类型为结构体指针的 nil，如 (*User)(nil)。
+//
+// 优先使用 Session 的 Mapper 解析列名，Session 的 Mapper 未实现 ColumnsMapper 接口时使用默认的 Mapper。
+func (sb *SelectBuilder) SelectsOf(src any, opts ...ColumnOption) *SelectBuilder {
+	var mapper Mapper
+	if sb.session != nil {
+		mapper = sb.session.Mapper()
+	}
+	var columns, err = ColumnsOf(mapper, src, opts...)
+	if err != nil {
+		return sb.Select(errorClause{err: err})
+	}
+	return sb.Selects(columns...)
+}
+
 func (sb *SelectBuilder) Select(sql any, args ...any) *SelectBuilder {
 	if sb.columns == nil {
 		sb.columns = NewClauses(',')
@@ -180,10 +206,17 @@ func (sb *SelectBuilder) Suffix(sql any, args ...any) *SelectBuilder {
 	return sb
 }
 
-func (sb *SelectBuilder) Write(w Writer) (err error) {
+func (sb *SelectBuilder) Validate() error {
 	if !sb.columns.valid() {
 		return errors.New("dbs: select clause must specify result columns")
 	}
+	return nil
+}
+
+func (sb *SelectBuilder) Write(w Writer) (err error) {
+	if err = sb.Validate(); err != nil {
+		return err
+	}
 
 	if sb.prefixes.valid() {
 		if err = sb.prefixes.Write(w); err != nil {
@@ -296,6 +329,8 @@ func (sb *SelectBuilder) SQL() (string, []any, error) {
 	defer buffer.Release()
 
 	buffer.UseDialect(sb.dialect)
+	buffer.UseSliceStrategy(sliceStrategy(sb.strategy, sb.session))
+	buffer.UseConverter(converterFromSession(sb.session))
 
 	if err := sb.Write(buffer); err != nil {
 		return "", nil, err
@@ -303,6 +338,10 @@ func (sb *SelectBuilder) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+func (sb *SelectBuilder) chunk(offset int) (string, []any, int, error) {
+	return chunkSQL(sb, sb.session, sb.dialect, sliceStrategy(sb.strategy, sb.session), offset)
+}
+
 func (sb *SelectBuilder) Count() *SelectBuilder {
 	var nsb = sb.Clone()
 	nsb.limit = nil
@@ -342,6 +381,7 @@ func (sb *SelectBuilder) countSubQuery(subQuery *SelectBuilder) *SelectBuilder {
 	var nsb = NewSelectBuilder()
 	nsb.dialect = sb.dialect
 	nsb.session = sb.session
+	nsb.strategy = sb.strategy
 	nsb.From("(?) AS count_query", subQuery)
 	nsb.Columns("COUNT(1)")
 	return nsb
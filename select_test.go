@@ -1,10 +1,17 @@
 package dbs_test
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/dialect/postgres"
+	"github.com/smartwalle/dbs/internal/dbstest"
 )
 
 func TestSelectBuilder(t *testing.T) {
@@ -46,3 +53,139 @@ func BenchmarkSelectBuilder_Pool(b *testing.B) {
 		pool.Put(sb)
 	}
 }
+
+func TestSelectBuilder_SliceStrategy(t *testing.T) {
+	var sb = dbs.NewSelectBuilder()
+	sb.UseDialect(postgres.Dialect())
+	sb.UseSliceStrategy(dbs.SliceArray)
+	sb.Selects("id", "name")
+	sb.Table("user")
+	sb.Where("id IN (?)", []int64{1, 2, 3})
+	sb.Where("status NOT IN (?)", []int{4, 5})
+	sb.OrderBy("FIELD(id, ?)", []int{3, 2, 1})
+	sql, args, err := sb.SQL()
+	if err != nil {
+		t.Fatal("生成 SQL 语句发生错误:", err)
+	}
+	if expectSQL := "SELECT id,name FROM user WHERE id = ANY($1) AND status <> ALL($2) ORDER BY FIELD(id, $3,$4,$5)"; sql != expectSQL {
+		t.Fatalf("期望 SQL: %s, 实际 SQL: %s", expectSQL, sql)
+	}
+	// 数组参数需要能够通过 database/sql 默认的参数转换
+	for idx, expect := range []driver.Value{"{1,2,3}", "{4,5}", int64(3), int64(2), int64(1)} {
+		var actual, err = driver.DefaultParameterConverter.ConvertValue(args[idx])
+		if err != nil {
+			t.Fatalf("转换第 %d 个参数发生错误: %v", idx, err)
+		}
+		if actual != expect {
+			t.Fatalf("期望第 %d 个参数: %v, 实际参数: %v", idx, expect, actual)
+		}
+	}
+
+	var nsb = sb.Clone().UseSliceStrategy(dbs.SliceExpand)
+	checkClause(t, nsb, "SELECT id,name FROM user WHERE id IN ($1,$2,$3) AND status NOT IN ($4,$5) ORDER BY FIELD(id, $6,$7,$8)", ExpectArgs(int64(1), int64(2), int64(3), 4, 5, 3, 2, 1))
+}
+
+func TestSelectBuilder_SliceChunk(t *testing.T) {
+	var d = dbstest.NewDriver(map[string]dbstest.Result{"": {Columns: []string{"id"}, Rows: [][]driver.Value{{int64(1)}}}})
+	var db = dbstest.Open(t, d)
+	db.UseSliceStrategy(dbs.SliceChunk)
+	db.UseSliceChunkSize(2)
+	var ctx = context.Background()
+
+	var sb = dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("id IN (?)", []int{1, 2, 3, 4, 5})
+	var users []*selectUser
+	if err := sb.Scan(ctx, &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("期望合并 3 条语句的结果, 实际结果数量: %d", len(users))
+	}
+	var expect = "SELECT id FROM user WHERE id IN (?,?); SELECT id FROM user WHERE id IN (?,?); SELECT id FROM user WHERE id IN (?)"
+	if actual := strings.Join(d.Statements(), "; "); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+
+	var user selectUser
+	if err := sb.Scan(ctx, &user); !errors.Is(err, dbs.ErrChunkDestination) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrChunkDestination, err)
+	}
+
+	// 合并结果无法保证排序、分页以及聚合的语义
+	for _, nsb := range []*dbs.SelectBuilder{
+		sb.Clone().OrderBy("id"),
+		sb.Clone().Limit(10),
+		sb.Clone().GroupBy("id"),
+		sb.Count(),
+		dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("id IN (?)", dbs.NewSelectBuilder().Selects("user_id").Table("log").Where("user_id IN (?)", []int{1, 2, 3}).Limit(10)),
+	} {
+		if err := nsb.Scan(ctx, &users); !errors.Is(err, dbs.ErrChunkUnsupported) {
+			t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrChunkUnsupported, err)
+		}
+	}
+
+	// 不需要拆分的语句、字符串以及不包含被拆分切片的子查询不受限制
+	for _, nsb := range []*dbs.SelectBuilder{
+		dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("id IN (?)", []int{1, 2}).OrderBy("id").Limit(1),
+		dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("id IN (SELECT user_id FROM log ORDER BY id LIMIT 10)").Where("id IN (?)", []int{1, 2, 3}),
+		dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("note <> 'order by (limit)'").Where("id IN (?)", []int{1, 2, 3}),
+	} {
+		if err := nsb.Scan(ctx, &users); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 拆分的长度需要减去其它占位符的数量
+	d.Reset()
+	db.UseDialect(limitedDialect{})
+	db.UseSliceChunkSize(10)
+	var lsb = dbs.NewSelectBuilder().UseSession(db).Selects("id").Table("user").Where("status = ? AND name = ?", 1, "a").Where("id IN (?)", []int{1, 2, 3, 4, 5})
+	if err := lsb.Scan(ctx, &users); err != nil {
+		t.Fatal(err)
+	}
+	expect = "SELECT id FROM user WHERE status = ? AND name = ? AND id IN (?,?); SELECT id FROM user WHERE status = ? AND name = ? AND id IN (?,?); " +
+		"SELECT id FROM user WHERE status = ? AND name = ? AND id IN (?)"
+	if actual := strings.Join(d.Statements(), "; "); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+}
+
+// limitedDialect 单条语句最多使用 4 个参数。
+type limitedDialect struct {
+}
+
+func (limitedDialect) WritePlaceholder(w dbs.Writer, idx int) error {
+	return w.WriteByte('?')
+}
+
+func (limitedDialect) MaxParameters() int {
+	return 4
+}
+
+func (limitedDialect) MaxBytes() int {
+	return 0
+}
+
+type selectUser struct {
+	Id        int64  `sql:"id"`
+	Name      string `sql:"name"`
+	Password  string `sql:"password"`
+	Ignore    string `sql:"-"`
+	CreatedAt int64  `sql:"created_at"`
+}
+
+func TestSelectBuilder_SelectsOf(t *testing.T) {
+	var sb = dbs.NewSelectBuilder()
+	sb.SelectsOf(selectUser{}, dbs.WithAlias("u"), dbs.WithExclude("password"))
+	sb.Table("user u")
+	sb.Where("u.id = ?", 1)
+	checkClause(t, sb, "SELECT u.id,u.name,u.created_at FROM user u WHERE u.id = ?", ExpectArgs(1))
+
+	var columns = dbs.Columns[selectUser]()
+	if expect := []string{"id", "name", "password", "created_at"}; !reflect.DeepEqual(columns, expect) {
+		t.Fatalf("期望列: %v, 实际列: %v", expect, columns)
+	}
+
+	if _, _, err := dbs.NewSelectBuilder().SelectsOf(1).Table("user").SQL(); !errors.Is(err, dbs.ErrInvalidColumnsValue) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrInvalidColumnsValue, err)
+	}
+}
@@ -0,0 +1,263 @@
+package dbs
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SliceStrategy 用于指定切片参数（如 Where("id IN (?)", ids)）生成 SQL 语句的方式。
+type SliceStrategy uint8
+
+const (
+	// SliceDefault 未指定策略，Builder 使用 Session 的策略，Session 也未指定时等同于 SliceExpand。
+	SliceDefault SliceStrategy = iota
+
+	// SliceExpand 将切片展开为多个占位符，如 id IN (?,?,?)。
+	SliceExpand
+
+	// SliceArray 将切片编码为 PostgreSQL 数组作为一个参数绑定，id IN (?) 会被改写为 id = ANY(?)，id NOT IN (?) 会被改写为 id <> ALL(?)，
+	// 适用于 PostgreSQL。无法改写的位置（如 FIELD(id, ?)）以及元素不是基本类型、time.Time 的切片仍然展开为多个占位符。
+	SliceArray
+
+	// SliceChunk 切片长度超过拆分长度（默认为 1000，参考 DB.UseSliceChunkSize）时，调用 Scan 会将查询拆分为多条语句依次执行，
+	// 并将结果合并到目标切片（或者以某一列作为键的 map，参考 ContextWithMapKey）中。
+	// 每条语句中只有第一个超出长度的切片会被拆分，其它位置以及 SQL()、Exec() 等方法仍然展开为多个占位符。
+	//
+	// 合并多条语句的结果无法保证 LIMIT、OFFSET、ORDER BY、GROUP BY、DISTINCT 以及聚合函数的语义，
+	// 最外层的语句或者包含被拆分切片的子查询中出现这些内容时 Scan 返回 ErrChunkUnsupported，字符串以及其它子查询中的内容不受影响。
+	// 拆分的长度会减去语句中其它占位符的数量，保证不超过 Dialect 允许的最大参数数量。
+	SliceChunk
+)
+
+const kSliceChunkSize = 1000
+
+var (
+	ErrChunkDestination = errors.New("dbs: chunked query must scan into a slice or keyed map")
+	ErrChunkUnsupported = errors.New("dbs: chunked query must not contain LIMIT, OFFSET, ORDER BY, GROUP BY, DISTINCT or aggregate functions")
+)
+
+type sliceStrategySession interface {
+	SliceStrategy() SliceStrategy
+}
+
+type sliceChunkSizeSession interface {
+	SliceChunkSize() int
+}
+
+// sliceChunkSize 返回 SliceChunk 策略拆分切片的长度，优先使用 Session 的设置，并且不超过 Dialect 允许的最大参数数量。
+func sliceChunkSize(session Session, dialect Dialect) int {
+	var size = kSliceChunkSize
+	if raw, ok := session.(sliceChunkSizeSession); ok && raw.SliceChunkSize() > 0 {
+		size = raw.SliceChunkSize()
+	}
+	if maxParameters, _ := dialectLimits(dialect); maxParameters > 0 && size > maxParameters {
+		size = maxParameters
+	}
+	return size
+}
+
+func sliceStrategy(strategy SliceStrategy, session Session) SliceStrategy {
+	if strategy != SliceDefault {
+		return strategy
+	}
+	if raw, ok := session.(sliceStrategySession); ok {
+		return raw.SliceStrategy()
+	}
+	return SliceDefault
+}
+
+func sliceStrategyFromWriter(w Writer) SliceStrategy {
+	if raw, ok := w.(interface{ SliceStrategy() SliceStrategy }); ok {
+		return raw.SliceStrategy()
+	}
+	return SliceDefault
+}
+
+// chunkable 由支持 SliceChunk 的 Builder 实现，offset 为被拆分切片的起始位置。
+//
+// 返回的 next 为下一条语句中被拆分切片的起始位置，没有切片被拆分或者已经是最后一条语句时为 0。
+type chunkable interface {
+	chunk(offset int) (query string, args []any, next int, err error)
+}
+
+func chunkSQL(clause SQLClause, session Session, dialect Dialect, strategy SliceStrategy, offset int) (string, []any, int, error) {
+	var size int
+	if strategy == SliceChunk {
+		size = sliceChunkSize(session, dialect)
+	}
+	var maxParameters, _ = dialectLimits(dialect)
+
+	for {
+		var buffer = NewBuffer()
+		buffer.UseDialect(dialect)
+		buffer.UseSliceStrategy(strategy)
+		buffer.UseConverter(converterFromSession(session))
+		buffer.chunkSize = size
+		buffer.chunkOffset = offset
+
+		if err := clause.Write(buffer); err != nil {
+			buffer.Release()
+			return "", nil, 0, err
+		}
+		if buffer.chunkTotal == 0 {
+			var query, args = buffer.String(), buffer.Arguments()
+			buffer.Release()
+			return query, args, 0, nil
+		}
+
+		// 拆分的切片与语句中其它的占位符一起不能超过 Dialect 允许的最大参数数量
+		if others := buffer.placeholderCount - buffer.chunkLength; maxParameters > 0 && size > 1 && size+others > maxParameters {
+			if size = maxParameters - others; size < 1 {
+				size = 1
+			}
+			buffer.Release()
+			continue
+		}
+
+		var query, args, pos, total = buffer.String(), buffer.Arguments(), buffer.chunkPos, buffer.chunkTotal
+		buffer.Release()
+		if keyword := chunkConflict(query, pos); keyword != "" {
+			return "", nil, 0, fmt.Errorf("%w: %s", ErrChunkUnsupported, keyword)
+		}
+		var next = offset + size
+		if next >= total {
+			next = 0
+		}
+		return query, args, next, nil
+	}
+}
+
+var chunkConflicts = []string{"LIMIT", "OFFSET", "ORDER BY", "GROUP BY", "DISTINCT", "COUNT(", "SUM(", "AVG(", "MIN(", "MAX("}
+
+// chunkConflict 返回拆分之后的语句中导致合并结果不正确的关键字，不区分大小写，没有时返回空字符串。
+//
+// 只检查最外层的语句以及包含拆分位置 pos 的子查询，字符串、引号中的标识符以及其它子查询中的内容不影响合并结果。
+func chunkConflict(query string, pos int) string {
+	var upper = []byte(strings.ToUpper(query))
+
+	// 括号的匹配位置，字符串以及引号中的括号不参与匹配
+	var closes = make(map[int]int)
+	var opens []int
+	scanQuery(upper, func(idx int, c byte) {
+		switch c {
+		case '(':
+			opens = append(opens, idx)
+		case ')':
+			if len(opens) > 0 {
+				closes[opens[len(opens)-1]] = idx
+				opens = opens[:len(opens)-1]
+			}
+		}
+	})
+
+	// 将不需要检查的内容替换为空格
+	var visible = make([]bool, len(upper))
+	opens = opens[:0]
+	scanQuery(upper, func(idx int, c byte) {
+		if c == ')' && len(opens) > 0 {
+			opens = opens[:len(opens)-1]
+		}
+		if len(opens) == 0 {
+			visible[idx] = true
+		} else {
+			var open = opens[len(opens)-1]
+			var end, ok = closes[open]
+			visible[idx] = open < pos && (!ok || end >= pos)
+		}
+		if c == '(' {
+			opens = append(opens, idx)
+		}
+	})
+	for idx := range upper {
+		if !visible[idx] {
+			upper[idx] = ' '
+		}
+	}
+
+	var text = string(upper)
+	for _, keyword := range chunkConflicts {
+		for start := 0; ; {
+			var idx = strings.Index(text[start:], keyword)
+			if idx < 0 {
+				break
+			}
+			idx += start
+			start = idx + len(keyword)
+			if (idx == 0 || !isIdentByte(text[idx-1])) && (keyword[len(keyword)-1] == '(' || start == len(text) || !isIdentByte(text[start])) {
+				return keyword
+			}
+		}
+	}
+	return ""
+}
+
+// scanQuery 依次使用 query 中不属于字符串以及引号中标识符的字符调用 fn。
+func scanQuery(query []byte, fn func(idx int, c byte)) {
+	var quote byte
+	for idx, c := range query {
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		default:
+			fn(idx, c)
+		}
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+func isSliceArgument(arg any) bool {
+	switch arg.(type) {
+	case SQLClause, driver.Valuer, []byte:
+		return false
+	}
+	var kind = reflect.ValueOf(arg).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// isArrayArgument 判断切片参数是否可以使用 SliceArray 策略作为一个数组参数绑定，元素为其它类型时仍然展开为多个占位符。
+func isArrayArgument(arg any) bool {
+	if !isSliceArgument(arg) {
+		return false
+	}
+	var rType = reflect.TypeOf(arg)
+	return rType.Kind() == reflect.Slice && isArrayElem(rType.Elem())
+}
+
+// arrayArgument 为使用 SliceArray 策略绑定的切片，通过 Value() 编码为 PostgreSQL 数组的字面量，database/sql 以及驱动不接受切片类型的参数。
+type arrayArgument struct {
+	value any
+}
+
+func (a arrayArgument) Value() (driver.Value, error) {
+	return encodeArray(reflect.ValueOf(a.value))
+}
+
+// splitIn 判断占位符之前的 SQL 片段是否以 IN ( 或 NOT IN ( 结尾，并返回去除该部分后的 SQL 片段以及对应的数组运算。
+func splitIn(sql string) (head, op string, ok bool) {
+	var trimmed = strings.TrimRight(sql, " ")
+	if !strings.HasSuffix(trimmed, "(") {
+		return sql, "", false
+	}
+	trimmed = strings.TrimRight(trimmed[:len(trimmed)-1], " ")
+	var n = len(trimmed)
+	if n < 3 || !strings.EqualFold(trimmed[n-2:], "IN") || trimmed[n-3] != ' ' {
+		return sql, "", false
+	}
+	trimmed = strings.TrimRight(trimmed[:n-2], " ")
+	if n = len(trimmed); n >= 4 && strings.EqualFold(trimmed[n-3:], "NOT") && trimmed[n-4] == ' ' {
+		return trimmed[:n-3], "<> ALL(", true
+	}
+	return trimmed + " ", "= ANY(", true
+}
@@ -130,6 +130,17 @@ func (s *Stmts) Mapper() Mapper {
 	return s.session.Mapper()
 }
 
+func (s *Stmts) SliceStrategy() SliceStrategy {
+	return sliceStrategy(SliceDefault, s.session)
+}
+
+func (s *Stmts) SliceChunkSize() int {
+	if raw, ok := s.session.(sliceChunkSizeSession); ok {
+		return raw.SliceChunkSize()
+	}
+	return 0
+}
+
 func (s *Stmts) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	return s.session.PrepareContext(ctx, query)
 }
@@ -0,0 +1,110 @@
+package dbs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrStrictDecode = errors.New("dbs: strict decode failed")
+
+// ContextMapper 是 Mapper 的可选接口，Builder 的 Scan() 方法以及 Query() 函数会优先调用 DecodeContext()。
+type ContextMapper interface {
+	DecodeContext(ctx context.Context, rows *sql.Rows, dest any) (int, error)
+}
+
+type strictKey struct{}
+
+// ContextWithStrict 为单次查询开启或者关闭严格模式，优先于 Mapper 的全局设置（参考 WithStrict）。
+func ContextWithStrict(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictKey{}, strict)
+}
+
+func strictFromContext(ctx context.Context, strict bool) bool {
+	if value, ok := ctx.Value(strictKey{}).(bool); ok {
+		return value
+	}
+	return strict
+}
+
+// WithStrict 开启严格模式，将查询结果映射到结构体时以下情况会返回 *DecodeError：
+//
+//	查询结果中存在结构体中没有对应字段的列；
+//	结构体中存在没有被查询结果中的列赋值的字段（关联结构体的字段除外）；
+//	NULL 被赋值给不能表示 NULL 的字段，如 int、string 等，指针以及实现了 sql.Scanner 接口的类型可以表示 NULL。
+func WithStrict() MapperOption {
+	return func(m *mapper) {
+		m.strict = true
+	}
+}
+
+// DecodeError 描述严格模式下映射失败的原因，可以通过 errors.As 获取，errors.Is(err, ErrStrictDecode) 返回 true。
+type DecodeError struct {
+	Type           reflect.Type
+	UnknownColumns []string
+	UnsetFields    []string
+	NullColumns    []string
+	Row            int // NullColumns 所在的数据行，从 0 开始
+}
+
+func (e *DecodeError) Error() string {
+	var parts = make([]string, 0, 3)
+	if len(e.UnknownColumns) > 0 {
+		parts = append(parts, fmt.Sprintf("unknown columns %v", e.UnknownColumns))
+	}
+	if len(e.UnsetFields) > 0 {
+		parts = append(parts, fmt.Sprintf("unpopulated fields %v", e.UnsetFields))
+	}
+	if len(e.NullColumns) > 0 {
+		parts = append(parts, fmt.Sprintf("NULL in non-nullable fields %v (row: %d)", e.NullColumns, e.Row))
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrStrictDecode, e.Type, strings.Join(parts, "; "))
+}
+
+func (e *DecodeError) Unwrap() error {
+	return ErrStrictDecode
+}
+
+func decode(ctx context.Context, mapper Mapper, rows *sql.Rows, dest any) (int, error) {
+	if raw, ok := mapper.(ContextMapper); ok {
+		return raw.DecodeContext(ctx, rows, dest)
+	}
+	return mapper.Decode(rows, dest)
+}
+
+// checkColumns 检查查询结果的列与结构体字段是否一一对应。
+func checkColumns(destType reflect.Type, mStruct structMetadata, columns []*sql.ColumnType, fields []*fieldMetadata) error {
+	var unknownColumns []string
+	var populated = make(map[*fieldMetadata]struct{}, len(fields))
+	for idx, field := range fields {
+		if field == nil {
+			unknownColumns = append(unknownColumns, columns[idx].Name())
+			continue
+		}
+		populated[field] = struct{}{}
+	}
+
+	var unsetFields []string
+	for _, column := range mStruct.columns {
+		if _, ok := populated[mStruct.fields[column]]; !ok {
+			unsetFields = append(unsetFields, column)
+		}
+	}
+
+	if len(unknownColumns) > 0 || len(unsetFields) > 0 {
+		return &DecodeError{Type: destType, UnknownColumns: unknownColumns, UnsetFields: unsetFields}
+	}
+	return nil
+}
+
+// isNullable 判断字段是否可以表示 NULL。
+func isNullable(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return reflect.PointerTo(fieldType).Implements(scannerType)
+}
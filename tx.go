@@ -3,13 +3,41 @@ package dbs
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 
 	"github.com/smartwalle/dbs/internal"
 )
 
+var ErrInvalidSavepoint = errors.New("dbs: invalid savepoint name")
+
 type Tx struct {
-	tx *sql.Tx
-	db *DB
+	tx  *sql.Tx
+	db  *DB
+	ctx context.Context // 开始事务时使用的 Context，用于执行 OnCommit 以及 OnRollback 注册的回调
+
+	mu         sync.Mutex
+	hooks      txHooks     // 不属于任何保存点的回调
+	savepoints []savepoint // 按照创建顺序排列的有效保存点
+	sequence   int         // 用于生成嵌套事务的保存点名称
+}
+
+type txHooks struct {
+	onCommit   []func(ctx context.Context)
+	onRollback []func(ctx context.Context, err error)
+}
+
+func (h *txHooks) merge(o txHooks) {
+	h.onCommit = append(h.onCommit, o.onCommit...)
+	h.onRollback = append(h.onRollback, o.onRollback...)
+}
+
+// savepoint 记录保存点以及在保存点中注册的回调。
+type savepoint struct {
+	name  string
+	hooks txHooks
 }
 
 func (tx *Tx) Tx() *sql.Tx {
@@ -28,6 +56,14 @@ func (tx *Tx) Mapper() Mapper {
 	return tx.db.Mapper()
 }
 
+func (tx *Tx) SliceStrategy() SliceStrategy {
+	return tx.db.SliceStrategy()
+}
+
+func (tx *Tx) SliceChunkSize() int {
+	return tx.db.SliceChunkSize()
+}
+
 func (tx *Tx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
 	return tx.tx.PrepareContext(ctx, query)
 }
@@ -48,12 +84,261 @@ func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *s
 	return tx.tx.QueryRowContext(ctx, query, args...)
 }
 
+// Commit 提交事务，提交成功之后执行 OnCommit 注册的回调，提交失败时执行 OnRollback 注册的回调。
+//
+// ctx 被取消时 database/sql 会自动回滚事务，之后调用 Commit() 返回 sql.ErrTxDone，同样会执行 OnRollback 注册的回调。
 func (tx *Tx) Commit() error {
-	return tx.tx.Commit()
+	var err = tx.tx.Commit()
+
+	// 回调在第一次执行之后会被清除，重复调用 Commit() 或者 Rollback() 不会再次执行
+	var hooks = tx.takeHooks()
+	if err != nil {
+		runRollbackHooks(tx.context(), hooks.onRollback, err)
+		return err
+	}
+	for _, fn := range hooks.onCommit {
+		fn(tx.context())
+	}
+	return nil
 }
 
+// Rollback 回滚事务，之后执行 OnRollback 注册的回调，回调的 err 为 nil。
 func (tx *Tx) Rollback() error {
-	return tx.tx.Rollback()
+	return tx.rollback(nil)
+}
+
+// rollback 回滚事务，cause 为导致回滚的错误，会传递给 OnRollback 注册的回调。
+//
+// ctx 被取消时 database/sql 已经回滚了事务，tx.tx.Rollback() 返回 sql.ErrTxDone，仍然需要执行回调。
+func (tx *Tx) rollback(cause error) error {
+	var err = tx.tx.Rollback()
+	runRollbackHooks(tx.context(), tx.takeHooks().onRollback, cause)
+	return err
+}
+
+// OnCommit 注册事务提交成功之后执行的回调，如发布事件、清除缓存，可以通过 TxFromContext(ctx) 获取 Tx：
+//
+//	if tx := dbs.TxFromContext(ctx); tx != nil {
+//		tx.OnCommit(func(ctx context.Context) {
+//			cache.Delete(key)
+//		})
+//	}
+//
+// 回调按照注册的顺序在 Commit() 返回之前执行，ctx 为开始事务时使用的 Context。
+// 在保存点中（如嵌套的 Transaction）注册的回调在回滚到该保存点时会被丢弃，释放保存点之后由外层决定是否执行。
+func (tx *Tx) OnCommit(fn func(ctx context.Context)) {
+	if fn == nil {
+		return
+	}
+	tx.mu.Lock()
+	var hooks = tx.currentHooks()
+	hooks.onCommit = append(hooks.onCommit, fn)
+	tx.mu.Unlock()
+}
+
+// OnRollback 注册事务回滚之后执行的回调，err 为导致回滚的错误（如 Transaction 中 fn 返回的错误、Commit() 失败的错误），直接调用 Rollback() 时为 nil。
+//
+// 在保存点中注册的回调在回滚到该保存点时执行，ctx 为 RollbackTo() 使用的 Context；其它回调在事务回滚时执行，ctx 为开始事务时使用的 Context。
+func (tx *Tx) OnRollback(fn func(ctx context.Context, err error)) {
+	if fn == nil {
+		return
+	}
+	tx.mu.Lock()
+	var hooks = tx.currentHooks()
+	hooks.onRollback = append(hooks.onRollback, fn)
+	tx.mu.Unlock()
+}
+
+// currentHooks 返回最内层保存点的回调，没有保存点时返回事务的回调，调用方需要持有 tx.mu。
+func (tx *Tx) currentHooks() *txHooks {
+	if len(tx.savepoints) > 0 {
+		return &tx.savepoints[len(tx.savepoints)-1].hooks
+	}
+	return &tx.hooks
+}
+
+// takeHooks 取出所有的回调，包括没有释放的保存点中的回调，保证回调只会执行一次。
+func (tx *Tx) takeHooks() txHooks {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	var hooks = tx.hooks
+	for _, sp := range tx.savepoints {
+		hooks.merge(sp.hooks)
+	}
+	tx.hooks = txHooks{}
+	tx.savepoints = nil
+	return hooks
+}
+
+func (tx *Tx) context() context.Context {
+	if tx.ctx != nil {
+		return tx.ctx
+	}
+	return context.Background()
+}
+
+func runRollbackHooks(ctx context.Context, hooks []func(ctx context.Context, err error), err error) {
+	for _, fn := range hooks {
+		fn(ctx, err)
+	}
+}
+
+// AfterCommit 在 ctx 中的事务提交成功之后执行 fn，ctx 中没有事务时立即执行 fn。
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if tx := TxFromContext(ctx); tx != nil {
+		tx.OnCommit(fn)
+		return
+	}
+	fn(ctx)
+}
+
+// AfterRollback 在 ctx 中的事务回滚之后执行 fn，ctx 中没有事务时不会执行 fn。
+func AfterRollback(ctx context.Context, fn func(ctx context.Context, err error)) {
+	if tx := TxFromContext(ctx); tx != nil {
+		tx.OnRollback(fn)
+	}
+}
+
+// Savepoint 创建保存点，name 只能包含字母、数字以及下划线，并且不能以数字开头。
+func (tx *Tx) Savepoint(ctx context.Context, name string) error {
+	if !validSavepoint(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidSavepoint, name)
+	}
+	if _, err := Exec(ctx, tx, dialectSavepointer(tx.Dialect()).SavepointSQL(name)); err != nil {
+		return err
+	}
+
+	tx.mu.Lock()
+	tx.savepoints = append(tx.savepoints, savepoint{name: name})
+	tx.mu.Unlock()
+	return nil
+}
+
+// RollbackTo 回滚到保存点，保存点本身仍然有效，之后创建的保存点会失效。
+//
+// 保存点中通过 OnCommit 注册的回调会被丢弃，通过 OnRollback 注册的回调会被执行，回调的 err 为 nil。
+func (tx *Tx) RollbackTo(ctx context.Context, name string) error {
+	return tx.rollbackTo(ctx, name, nil)
+}
+
+func (tx *Tx) rollbackTo(ctx context.Context, name string, cause error) error {
+	if !validSavepoint(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidSavepoint, name)
+	}
+	if _, err := Exec(ctx, tx, dialectSavepointer(tx.Dialect()).RollbackToSQL(name)); err != nil {
+		return err
+	}
+
+	var hooks txHooks
+	tx.mu.Lock()
+	if idx := tx.savepointIndex(name); idx >= 0 {
+		for _, sp := range tx.savepoints[idx:] {
+			hooks.merge(sp.hooks)
+		}
+		tx.savepoints[idx].hooks = txHooks{}
+		tx.savepoints = tx.savepoints[:idx+1]
+	}
+	tx.mu.Unlock()
+
+	runRollbackHooks(ctx, hooks.onRollback, cause)
+	return nil
+}
+
+// Release 释放保存点，保存点中的修改会保留在事务中，保存点本身以及之后创建的保存点会失效。
+func (tx *Tx) Release(ctx context.Context, name string) error {
+	if !validSavepoint(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidSavepoint, name)
+	}
+	if query := dialectSavepointer(tx.Dialect()).ReleaseSQL(name); query != "" {
+		if _, err := Exec(ctx, tx, query); err != nil {
+			return err
+		}
+	}
+
+	tx.mu.Lock()
+	if idx := tx.savepointIndex(name); idx >= 0 {
+		// 释放的保存点中注册的回调属于外层
+		var parent = &tx.hooks
+		if idx > 0 {
+			parent = &tx.savepoints[idx-1].hooks
+		}
+		for _, sp := range tx.savepoints[idx:] {
+			parent.merge(sp.hooks)
+		}
+		tx.savepoints = tx.savepoints[:idx]
+	}
+	tx.mu.Unlock()
+	return nil
+}
+
+// savepointIndex 返回最后一个名为 name 的保存点的位置，调用方需要持有 tx.mu。
+func (tx *Tx) savepointIndex(name string) int {
+	for idx := len(tx.savepoints) - 1; idx >= 0; idx-- {
+		if tx.savepoints[idx].name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// nested 在保存点中执行 fn，fn 返回错误时回滚到保存点，否则释放保存点，用于嵌套的事务。
+func (tx *Tx) nested(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx.mu.Lock()
+	tx.sequence++
+	var name = "dbs_savepoint_" + strconv.Itoa(tx.sequence)
+	tx.mu.Unlock()
+
+	if err = tx.Savepoint(ctx, name); err != nil {
+		return err
+	}
+
+	if err = fn(ctx); err != nil {
+		if rErr := tx.rollbackTo(ctx, name, err); rErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint: %v)", err, rErr)
+		}
+		// 回滚之后保存点仍然有效，释放失败不影响外层事务，忽略该错误
+		_ = tx.Release(ctx, name)
+		return err
+	}
+	return tx.Release(ctx, name)
+}
+
+func validSavepoint(name string) bool {
+	if name == "" {
+		return false
+	}
+	for idx, c := range name {
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && idx > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// transaction 在事务中执行 fn，ctx 中已经存在事务时在保存点中执行 fn。
+func transaction(ctx context.Context, db Database, fn func(ctx context.Context) error, opts *sql.TxOptions) (err error) {
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx.nested(ctx, fn)
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.rollback(err)
+		}
+	}()
+
+	if err = fn(tx.WithContext(ctx)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (tx *Tx) WithContext(ctx context.Context) context.Context {
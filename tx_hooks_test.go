@@ -0,0 +1,187 @@
+package dbs_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/internal/dbstest"
+)
+
+// hookRecorder 按照执行顺序记录回调。
+type hookRecorder struct {
+	events []string
+}
+
+func (r *hookRecorder) commit(name string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		r.events = append(r.events, name)
+	}
+}
+
+func (r *hookRecorder) rollback(name string) func(ctx context.Context, err error) {
+	return func(ctx context.Context, err error) {
+		if err != nil {
+			name += "(" + err.Error() + ")"
+		}
+		r.events = append(r.events, name)
+	}
+}
+
+func (r *hookRecorder) String() string {
+	return strings.Join(r.events, ", ")
+}
+
+func TestTx_OnCommit(t *testing.T) {
+	var db, _ = openTxDB(t)
+	var repo = dbs.NewRepository[txUser](db)
+	var ctx = context.Background()
+	var errInner = errors.New("inner")
+	var r = &hookRecorder{}
+
+	var err = repo.Transaction(ctx, func(ctx context.Context) error {
+		dbs.AfterCommit(ctx, r.commit("outer"))
+		dbs.AfterRollback(ctx, r.rollback("outer rollback"))
+
+		// 内层回滚，内层注册的 OnCommit 回调不会执行
+		_ = repo.Transaction(ctx, func(ctx context.Context) error {
+			dbs.AfterCommit(ctx, r.commit("inner1"))
+			dbs.AfterRollback(ctx, r.rollback("inner1 rollback"))
+			return errInner
+		})
+
+		// 内层成功，回调在外层事务提交之后执行
+		_ = repo.Transaction(ctx, func(ctx context.Context) error {
+			dbs.AfterCommit(ctx, r.commit("inner2"))
+			return nil
+		})
+
+		if len(r.events) != 1 {
+			t.Fatalf("期望提交之前只执行内层的回滚回调, 实际执行: %s", r)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = "inner1 rollback(inner), outer, inner2"
+	if actual := r.String(); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+
+	// ctx 中没有事务时立即执行
+	r.events = nil
+	dbs.AfterCommit(ctx, r.commit("direct"))
+	dbs.AfterRollback(ctx, r.rollback("direct rollback"))
+	if actual := r.String(); actual != "direct" {
+		t.Fatalf("期望执行: %s, 实际执行: %s", "direct", actual)
+	}
+}
+
+func TestTx_OnRollback(t *testing.T) {
+	var db, _ = openTxDB(t)
+	var repo = dbs.NewRepository[txUser](db)
+	var ctx = context.Background()
+	var errOuter = errors.New("outer")
+	var r = &hookRecorder{}
+
+	var err = repo.Transaction(ctx, func(ctx context.Context) error {
+		var tx = dbs.TxFromContext(ctx)
+		tx.OnCommit(r.commit("outer"))
+		tx.OnRollback(r.rollback("outer rollback"))
+
+		_ = repo.Transaction(ctx, func(ctx context.Context) error {
+			tx.OnCommit(r.commit("inner"))
+			tx.OnRollback(r.rollback("inner rollback"))
+			return nil
+		})
+		return errOuter
+	})
+	if !errors.Is(err, errOuter) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", errOuter, err)
+	}
+
+	var expect = "outer rollback(outer), inner rollback(outer)"
+	if actual := r.String(); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+
+	// 直接调用 Rollback()，回调只会执行一次
+	r.events = nil
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Savepoint(ctx, "sp1"); err != nil {
+		t.Fatal(err)
+	}
+	tx.OnCommit(r.commit("commit"))
+	tx.OnRollback(r.rollback("rollback"))
+	if err = tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	_ = tx.Rollback()
+	_ = tx.Commit()
+	if actual := r.String(); actual != "rollback" {
+		t.Fatalf("期望执行: %s, 实际执行: %s", "rollback", actual)
+	}
+}
+
+// waitRollback 等待 database/sql 在 ctx 被取消之后自动回滚事务。
+func waitRollback(t *testing.T, d *dbstest.Driver) {
+	for idx := 0; idx < 1000; idx++ {
+		if strings.HasSuffix(statements(d), "ROLLBACK") {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("事务没有被自动回滚")
+}
+
+func TestTx_OnRollback_Canceled(t *testing.T) {
+	var db, d = openTxDB(t)
+	var repo = dbs.NewRepository[txUser](db)
+	var r = &hookRecorder{}
+
+	// ctx 被取消时 database/sql 会自动回滚事务，回调仍然需要执行
+	var ctx, cancel = context.WithCancel(context.Background())
+	var err = repo.Transaction(ctx, func(ctx context.Context) error {
+		dbs.AfterCommit(ctx, r.commit("commit"))
+		dbs.AfterRollback(ctx, r.rollback("rollback"))
+		cancel()
+		waitRollback(t, d)
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", context.Canceled, err)
+	}
+	if expect := "rollback(context canceled)"; r.String() != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, r)
+	}
+
+	// 事务被自动回滚之后调用 Commit()
+	r.events = nil
+	ctx, cancel = context.WithCancel(context.Background())
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.OnCommit(r.commit("commit"))
+	tx.OnRollback(func(ctx context.Context, err error) {
+		r.events = append(r.events, "rollback")
+	})
+	cancel()
+	waitRollback(t, d)
+	if err = tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", sql.ErrTxDone, err)
+	}
+	_ = tx.Rollback()
+	if expect := "rollback"; r.String() != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, r)
+	}
+}
@@ -0,0 +1,122 @@
+package dbs_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/smartwalle/dbs"
+	"github.com/smartwalle/dbs/internal/dbstest"
+)
+
+func openTxDB(t *testing.T) (*dbs.DB, *dbstest.Driver) {
+	var d = dbstest.NewDriver(nil)
+	return dbstest.Open(t, d), d
+}
+
+// statements 返回按照顺序执行的语句，使用 ; 分隔。
+func statements(d *dbstest.Driver) string {
+	return strings.Join(d.Statements(), "; ")
+}
+
+type txUser struct {
+	Id int64 `sql:"id"`
+}
+
+func (txUser) TableName() string {
+	return "user"
+}
+
+func (txUser) PrimaryKey() string {
+	return "id"
+}
+
+func execInTx(ctx context.Context, query string) error {
+	_, err := dbs.Exec(ctx, dbs.TxFromContext(ctx), query)
+	return err
+}
+
+func TestRepository_NestedTransaction(t *testing.T) {
+	var db, d = openTxDB(t)
+	var repo = dbs.NewRepository[txUser](db)
+	var ctx = context.Background()
+	var errInner = errors.New("inner")
+
+	var err = repo.Transaction(ctx, func(ctx context.Context) error {
+		if err := execInTx(ctx, "INSERT 1"); err != nil {
+			return err
+		}
+		if err := repo.Transaction(ctx, func(ctx context.Context) error {
+			if err := execInTx(ctx, "INSERT 2"); err != nil {
+				return err
+			}
+			return errInner
+		}); !errors.Is(err, errInner) {
+			t.Fatalf("期望错误: %v, 实际错误: %v", errInner, err)
+		}
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			return execInTx(ctx, "INSERT 3")
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = "BEGIN; INSERT 1; SAVEPOINT dbs_savepoint_1; INSERT 2; ROLLBACK TO SAVEPOINT dbs_savepoint_1; RELEASE SAVEPOINT dbs_savepoint_1; " +
+		"SAVEPOINT dbs_savepoint_2; INSERT 3; RELEASE SAVEPOINT dbs_savepoint_2; COMMIT"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+}
+
+// sqlServerDialect 使用 SQL Server 的保存点语法，SQL Server 不支持释放保存点。
+type sqlServerDialect struct {
+}
+
+func (sqlServerDialect) WritePlaceholder(w dbs.Writer, idx int) error {
+	return w.WriteByte('?')
+}
+
+func (sqlServerDialect) SavepointSQL(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+func (sqlServerDialect) RollbackToSQL(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+func (sqlServerDialect) ReleaseSQL(name string) string {
+	return ""
+}
+
+func TestTx_Savepoint(t *testing.T) {
+	var db, d = openTxDB(t)
+	db.UseDialect(sqlServerDialect{})
+	var ctx = context.Background()
+
+	var tx, err = db.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Savepoint(ctx, "a; DROP TABLE user"); !errors.Is(err, dbs.ErrInvalidSavepoint) {
+		t.Fatalf("期望错误: %v, 实际错误: %v", dbs.ErrInvalidSavepoint, err)
+	}
+	if err = tx.Savepoint(ctx, "sp1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.RollbackTo(ctx, "sp1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Release(ctx, "sp1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expect = "BEGIN; SAVE TRANSACTION sp1; ROLLBACK TRANSACTION sp1; COMMIT"
+	if actual := statements(d); actual != expect {
+		t.Fatalf("期望执行: %s, 实际执行: %s", expect, actual)
+	}
+}
@@ -10,6 +10,7 @@ import (
 type UpdateBuilder struct {
 	dialect  Dialect
 	session  Session
+	strategy SliceStrategy
 	prefixes *Clauses
 	options  *Clauses
 	table    string
@@ -20,14 +21,41 @@ type UpdateBuilder struct {
 	suffixes *Clauses
 }
 
+var ErrDuplicateSetColumn = errors.New("dbs: update clause sets the same column more than once")
+
 func NewUpdateBuilder() *UpdateBuilder {
 	var sb = &UpdateBuilder{}
 	return sb
 }
 
+func (ub *UpdateBuilder) Clone() *UpdateBuilder {
+	if ub == nil {
+		return nil
+	}
+	var nub = &UpdateBuilder{}
+	nub.dialect = ub.dialect
+	nub.session = ub.session
+	nub.strategy = ub.strategy
+	nub.prefixes = ub.prefixes.Clone()
+	nub.options = ub.options.Clone()
+	nub.table = ub.table
+	if len(ub.sets) > 0 {
+		nub.sets = make([]Set, len(ub.sets))
+		for i, set := range ub.sets {
+			nub.sets[i] = set.Clone()
+		}
+	}
+	nub.wheres = ub.wheres.Clone()
+	nub.orderBys = ub.orderBys.Clone()
+	nub.limit = clone(ub.limit)
+	nub.suffixes = ub.suffixes.Clone()
+	return nub
+}
+
 func (ub *UpdateBuilder) Reset() {
 	ub.dialect = nil
 	ub.session = nil
+	ub.strategy = SliceDefault
 	ub.prefixes.reset()
 	ub.options.reset()
 	ub.table = ""
@@ -51,6 +79,11 @@ func (ub *UpdateBuilder) UseSession(session Session) *UpdateBuilder {
 	return ub
 }
 
+func (ub *UpdateBuilder) UseSliceStrategy(strategy SliceStrategy) *UpdateBuilder {
+	ub.strategy = strategy
+	return ub
+}
+
 func (ub *UpdateBuilder) Prefix(sql any, args ...any) *UpdateBuilder {
 	if ub.prefixes == nil {
 		ub.prefixes = NewClauses(' ')
@@ -124,7 +157,7 @@ func (ub *UpdateBuilder) Suffix(sql any, args ...any) *UpdateBuilder {
 	return ub
 }
 
-func (ub *UpdateBuilder) Write(w Writer) (err error) {
+func (ub *UpdateBuilder) Validate() error {
 	if len(ub.table) == 0 {
 		return errors.New("dbs: update clause must specify a table")
 	}
@@ -135,6 +168,40 @@ func (ub *UpdateBuilder) Write(w Writer) (err error) {
 		return errors.New("dbs: update clause must specify a where clause")
 	}
 
+	if column, ok := ub.duplicateColumn(); ok {
+		return &ValidationError{Row: -1, Column: column, Err: ErrDuplicateSetColumn}
+	}
+	return nil
+}
+
+// duplicateColumn 查找重复的 SET 字段，字段较少时直接遍历，避免每次生成 SQL 都分配 map。
+func (ub *UpdateBuilder) duplicateColumn() (string, bool) {
+	if len(ub.sets) <= 16 {
+		for idx, set := range ub.sets {
+			for _, prev := range ub.sets[:idx] {
+				if prev.column == set.column {
+					return set.column, true
+				}
+			}
+		}
+		return "", false
+	}
+
+	var columns = make(map[string]struct{}, len(ub.sets))
+	for _, set := range ub.sets {
+		if _, ok := columns[set.column]; ok {
+			return set.column, true
+		}
+		columns[set.column] = struct{}{}
+	}
+	return "", false
+}
+
+func (ub *UpdateBuilder) Write(w Writer) (err error) {
+	if err = ub.Validate(); err != nil {
+		return err
+	}
+
 	if ub.prefixes.valid() {
 		if err = ub.prefixes.Write(w); err != nil {
 			return err
@@ -218,6 +285,8 @@ func (ub *UpdateBuilder) SQL() (string, []any, error) {
 	defer buffer.Release()
 
 	buffer.UseDialect(ub.dialect)
+	buffer.UseSliceStrategy(sliceStrategy(ub.strategy, ub.session))
+	buffer.UseConverter(converterFromSession(ub.session))
 
 	if err := ub.Write(buffer); err != nil {
 		return "", nil, err
@@ -225,6 +294,10 @@ func (ub *UpdateBuilder) SQL() (string, []any, error) {
 	return buffer.String(), buffer.Arguments(), nil
 }
 
+func (ub *UpdateBuilder) chunk(offset int) (string, []any, int, error) {
+	return chunkSQL(ub, ub.session, ub.dialect, sliceStrategy(ub.strategy, ub.session), offset)
+}
+
 func (ub *UpdateBuilder) Scan(ctx context.Context, dest any) error {
 	return scan(ctx, ub.session, ub, dest)
 }
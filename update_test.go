@@ -17,6 +17,24 @@ func TestUpdateBuilder(t *testing.T) {
 	t.Log(ub.SQL())
 }
 
+func TestUpdateBuilder_Clone(t *testing.T) {
+	var ub = dbs.NewUpdateBuilder()
+	ub.Table("user")
+	ub.Set("status", 1)
+	ub.Where("deleted_at IS NULL")
+
+	var nub = ub.Clone()
+	nub.Set("name", "Sample")
+	nub.Where("id = ?", 10)
+
+	checkClause(t, ub, "UPDATE user SET status=? WHERE deleted_at IS NULL", ExpectArgs(1))
+	checkClause(t, nub, "UPDATE user SET status=?,name=? WHERE deleted_at IS NULL AND id = ?", ExpectArgs(1, "Sample", 10))
+
+	var rb = dbs.NewUpdateBuilder()
+	rb.Reset()
+	rb.Clone().Reset()
+}
+
 func BenchmarkUpdateBuilder(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		var ub = dbs.NewUpdateBuilder()